@@ -0,0 +1,117 @@
+package lettr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// TrackingDefaults contains the account's default tracking settings, applied
+// to sends that don't set SendEmailOptions.OpenTracking or ClickTracking.
+type TrackingDefaults struct {
+	OpenTracking  bool `json:"open_tracking"`
+	ClickTracking bool `json:"click_tracking"`
+}
+
+// TrackingDefaultsResponse is the response from getting tracking defaults.
+type TrackingDefaultsResponse struct {
+	ResponseMeta
+	Message string           `json:"message"`
+	Data    TrackingDefaults `json:"data"`
+}
+
+// TrackingDefaults retrieves the account's default open and click tracking
+// settings.
+//
+// Example:
+//
+//	defaults, err := client.TrackingDefaults(ctx)
+//	if err == nil && defaults.OpenTracking {
+//	    log.Println("open tracking is on by default")
+//	}
+func (c *Client) TrackingDefaults(ctx context.Context, opts ...RequestOption) (*TrackingDefaults, error) {
+	req, err := c.newRequest(ctx, "client.tracking_defaults", http.MethodGet, "account/tracking", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TrackingDefaultsResponse
+	if _, err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// AccountLimits contains the account's effective sending limits.
+type AccountLimits struct {
+	// MaxRecipientsPerMessage is the most To, Cc and Bcc recipients a single
+	// send may have.
+	MaxRecipientsPerMessage int `json:"max_recipients_per_message"`
+
+	// DailyLimit is the most emails the account may send per day.
+	DailyLimit int `json:"daily_limit"`
+}
+
+// AccountLimitsResponse is the response from getting account limits.
+type AccountLimitsResponse struct {
+	ResponseMeta
+	Message string        `json:"message"`
+	Data    AccountLimits `json:"data"`
+}
+
+// Limits retrieves the account's sending limits. The result is cached for
+// the lifetime of the client, and once cached, Emails.Send and
+// Emails.Schedule reject sends with more recipients than
+// MaxRecipientsPerMessage before making a request.
+//
+// Example:
+//
+//	limits, err := client.Limits(ctx)
+//	if err == nil {
+//	    log.Printf("up to %d recipients per message", limits.MaxRecipientsPerMessage)
+//	}
+func (c *Client) Limits(ctx context.Context, opts ...RequestOption) (*AccountLimits, error) {
+	c.limitsMu.Lock()
+	cached := c.limits
+	c.limitsMu.Unlock()
+	if cached != nil {
+		limits := *cached
+		return &limits, nil
+	}
+
+	// Fetch without holding limitsMu, so concurrent sends checking the
+	// cache are not blocked on the request. Concurrent first calls may
+	// each fetch; the last one cached wins.
+	req, err := c.newRequest(ctx, "client.limits", http.MethodGet, "account/limits", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AccountLimitsResponse
+	if _, err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	c.limitsMu.Lock()
+	c.limits = &resp.Data
+	c.limitsMu.Unlock()
+
+	limits := resp.Data
+	return &limits, nil
+}
+
+// validateRecipientCount checks params against the cached account limits,
+// if Limits has been called.
+func (c *Client) validateRecipientCount(params *SendEmailRequest) error {
+	c.limitsMu.Lock()
+	limits := c.limits
+	c.limitsMu.Unlock()
+
+	if limits == nil || limits.MaxRecipientsPerMessage <= 0 {
+		return nil
+	}
+	if n := len(params.To) + len(params.Cc) + len(params.Bcc); n > limits.MaxRecipientsPerMessage {
+		return fmt.Errorf("lettr: %d recipients exceeds the account limit of %d per message", n, limits.MaxRecipientsPerMessage)
+	}
+	return nil
+}
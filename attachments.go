@@ -0,0 +1,157 @@
+package lettr
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// attachmentChunkSize is the number of raw bytes UploadAttachment sends per
+// request. Chunks are base64-encoded on the wire.
+var attachmentChunkSize = 4 << 20
+
+// NewAttachmentFromFile reads the file at path into an Attachment named
+// after the file, with Data base64-encoded and Type detected as described
+// for NewAttachmentFromReader.
+//
+// Example:
+//
+//	invoice, err := lettr.NewAttachmentFromFile("invoices/2024-06.pdf")
+//	if err != nil {
+//	    return err
+//	}
+//	params.Attachments = append(params.Attachments, invoice)
+func NewAttachmentFromFile(path string) (Attachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("lettr: failed to open attachment: %w", err)
+	}
+	defer f.Close()
+	return NewAttachmentFromReader(filepath.Base(path), f)
+}
+
+// NewAttachmentFromReader reads r into an Attachment called name, with Data
+// base64-encoded. Type is taken from name's extension, falling back to
+// sniffing the content when the extension is unknown. Empty content is an
+// error.
+func NewAttachmentFromReader(name string, r io.Reader) (Attachment, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("lettr: failed to read attachment %q: %w", name, err)
+	}
+	if len(data) == 0 {
+		return Attachment{}, fmt.Errorf("lettr: attachment %q is empty", name)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return Attachment{
+		Name: name,
+		Type: contentType,
+		Data: base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// createUploadRequest is the request body for starting an attachment upload.
+type createUploadRequest struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// createUploadResponse is the response from starting an attachment upload.
+type createUploadResponse struct {
+	Message string `json:"message"`
+	Data    struct {
+		UploadID string `json:"upload_id"`
+	} `json:"data"`
+}
+
+// uploadChunkRequest is the request body for a single upload chunk.
+type uploadChunkRequest struct {
+	Offset int64  `json:"offset"`
+	Data   string `json:"data"`
+}
+
+// completeUploadResponse is the response from completing an attachment upload.
+type completeUploadResponse struct {
+	Message string `json:"message"`
+	Data    struct {
+		AttachmentRef string `json:"attachment_ref"`
+	} `json:"data"`
+}
+
+// UploadAttachment uploads the contents of r as an attachment named name with
+// MIME type contentType, and returns a reference to pass in
+// SendEmailRequest.AttachmentRefs. The content is sent in chunks through an
+// upload session, so large files never travel in a single request.
+//
+// Example:
+//
+//	f, err := os.Open("report.pdf")
+//	ref, err := client.Emails.UploadAttachment(ctx, f, "report.pdf", "application/pdf")
+//	resp, err := client.Emails.Send(ctx, &lettr.SendEmailRequest{
+//	    // ...
+//	    AttachmentRefs: []string{ref},
+//	})
+func (s *EmailService) UploadAttachment(ctx context.Context, r io.Reader, name, contentType string, opts ...RequestOption) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("lettr: attachment name must not be empty")
+	}
+
+	req, err := s.client.newRequest(ctx, "emails.upload_attachment", http.MethodPost, "emails/attachments/uploads", &createUploadRequest{Name: name, Type: contentType}, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var created createUploadResponse
+	if _, err := s.client.do(req, &created); err != nil {
+		return "", err
+	}
+	base := fmt.Sprintf("emails/attachments/uploads/%s", url.PathEscape(created.Data.UploadID))
+
+	buf := make([]byte, attachmentChunkSize)
+	var offset int64
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk := &uploadChunkRequest{
+				Offset: offset,
+				Data:   base64.StdEncoding.EncodeToString(buf[:n]),
+			}
+			req, err := s.client.newRequest(ctx, "emails.upload_attachment", http.MethodPost, base+"/chunks", chunk, opts...)
+			if err != nil {
+				return "", err
+			}
+			if _, err := s.client.do(req, nil); err != nil {
+				return "", err
+			}
+			offset += int64(n)
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return "", fmt.Errorf("lettr: failed to read attachment: %w", readErr)
+		}
+	}
+
+	req, err = s.client.newRequest(ctx, "emails.upload_attachment", http.MethodPost, base+"/complete", nil, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var completed completeUploadResponse
+	if _, err := s.client.do(req, &completed); err != nil {
+		return "", err
+	}
+	return completed.Data.AttachmentRef, nil
+}
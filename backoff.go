@@ -0,0 +1,116 @@
+package lettr
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BackoffStrategy computes how long to wait before a retry. attempt is the
+// 1-based number of the retry about to be made, so NextDelay(1) is the wait
+// before the first retry. Configure one with WithBackoff or
+// RetryConfig.Backoff.
+type BackoffStrategy interface {
+	NextDelay(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same Delay before every retry.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// NextDelay implements BackoffStrategy.
+func (b ConstantBackoff) NextDelay(attempt int) time.Duration {
+	return b.Delay
+}
+
+// LinearBackoff waits Base multiplied by the attempt number, capped at Max
+// when Max is positive.
+type LinearBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements BackoffStrategy.
+func (b LinearBackoff) NextDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	return capDelay(b.Base*time.Duration(attempt), b.Max)
+}
+
+// ExponentialBackoff doubles the wait with every attempt, starting at Base
+// and capped at Max when Max is positive.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements BackoffStrategy.
+func (b ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := b.Base
+	for i := 1; i < attempt; i++ {
+		if b.Max > 0 && d >= b.Max {
+			return b.Max
+		}
+		if d > math.MaxInt64/2 {
+			return time.Duration(math.MaxInt64)
+		}
+		d *= 2
+	}
+	return capDelay(d, b.Max)
+}
+
+// DecorrelatedJitterBackoff picks each wait at random between Base and three
+// times the previous wait, capped at Max. The randomness spreads out retries
+// from many clients hitting the same failure. Create one with
+// NewDecorrelatedJitterBackoff; it is safe for concurrent use, though
+// concurrent callers share the "previous wait" state.
+type DecorrelatedJitterBackoff struct {
+	base time.Duration
+	max  time.Duration
+
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	prev time.Duration
+}
+
+// NewDecorrelatedJitterBackoff returns a decorrelated jitter strategy with
+// the given base and maximum delays.
+func NewDecorrelatedJitterBackoff(base, max time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{
+		base: base,
+		max:  max,
+		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NextDelay implements BackoffStrategy. Attempt 1 restarts the sequence.
+func (b *DecorrelatedJitterBackoff) NextDelay(attempt int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if attempt <= 1 || b.prev < b.base {
+		b.prev = b.base
+	}
+	upper := b.prev * 3
+	d := b.base
+	if upper > b.base {
+		d += time.Duration(b.rnd.Int63n(int64(upper - b.base)))
+	}
+	d = capDelay(d, b.max)
+	b.prev = d
+	return d
+}
+
+// capDelay limits d to max when max is positive.
+func capDelay(d, max time.Duration) time.Duration {
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
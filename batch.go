@@ -0,0 +1,404 @@
+package lettr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBatchConcurrency is the number of sends SendBatch runs at once when
+// SendBatchRequest.Concurrency is not set.
+const defaultBatchConcurrency = 4
+
+// SendBatchRequest sends one message to many recipients, each with their own
+// substitution data and metadata.
+type SendBatchRequest struct {
+	// Email is the message shared by every recipient. Its To, Cc and Bcc
+	// must be empty; each recipient is sent a copy addressed to them alone.
+	// Its SubstitutionData and Metadata apply to every recipient, with the
+	// recipient's own values taking precedence.
+	Email SendEmailRequest
+
+	// Recipients receive one email each (required).
+	Recipients []BatchRecipient
+
+	// Concurrency is the maximum number of sends in flight (default 4).
+	Concurrency int
+
+	// ErrorOnFailure makes SendBatch return a *BatchPartialError, alongside
+	// the result, if any recipient failed. By default failures are only
+	// reported in the result.
+	ErrorOnFailure bool
+}
+
+// BatchRecipient is a single recipient of a SendBatchRequest or Campaign.
+type BatchRecipient struct {
+	// Email is the recipient email address.
+	Email string
+
+	// SubstitutionData contains this recipient's template variables.
+	SubstitutionData map[string]string
+
+	// Metadata contains this recipient's custom key-value pairs for tracking.
+	Metadata map[string]string
+
+	// SendAt schedules this recipient's email with EmailService.Schedule,
+	// e.g. to stagger a campaign across time zones. The zero value sends
+	// immediately.
+	SendAt time.Time
+}
+
+// SendBatchResult aggregates the outcome of a SendBatch call or Campaign run.
+type SendBatchResult struct {
+	// Sent is the number of recipients whose send was accepted by the API.
+	Sent int
+
+	// Failed is the number of recipients whose send returned an error or
+	// was rejected by the API.
+	Failed int
+
+	// Recipients holds the per-recipient outcomes, in recipient order.
+	Recipients []BatchRecipientResult
+}
+
+// BatchRecipientResult is the outcome of sending to one batch recipient.
+type BatchRecipientResult struct {
+	// Email is the recipient email address.
+	Email string
+
+	// RequestID is the transmission ID of the accepted or scheduled send.
+	RequestID string
+
+	// Err is the send error, or nil if the send was accepted.
+	Err error
+}
+
+// BatchPartialError is returned by SendBatch, when
+// SendBatchRequest.ErrorOnFailure is set, if some recipients failed. The
+// result is also returned from the call.
+type BatchPartialError struct {
+	// Result holds every recipient's outcome, including the successful ones.
+	Result *SendBatchResult
+}
+
+// Error implements the error interface.
+func (e *BatchPartialError) Error() string {
+	return fmt.Sprintf("lettr: %d of %d batch recipients failed", e.Result.Failed, len(e.Result.Recipients))
+}
+
+// Unwrap returns the failed recipients' errors so errors.Is and errors.As can
+// match any of them.
+func (e *BatchPartialError) Unwrap() []error {
+	errs := make([]error, 0, e.Result.Failed)
+	for _, r := range e.Result.Recipients {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	return errs
+}
+
+// validate checks the batch before anything is sent. Scheduled recipients
+// must have a SendAt after now.
+func (b *SendBatchRequest) validate(now time.Time) error {
+	if len(b.Email.To) > 0 || len(b.Email.Cc) > 0 || len(b.Email.Bcc) > 0 {
+		return fmt.Errorf("lettr: batch email must not set To, Cc or Bcc; use Recipients")
+	}
+	if len(b.Recipients) == 0 {
+		return fmt.Errorf("lettr: batch has no recipients")
+	}
+	if b.Concurrency < 0 {
+		return fmt.Errorf("lettr: batch concurrency must not be negative, got %d", b.Concurrency)
+	}
+	for _, r := range b.Recipients {
+		if err := validateAddresses("to", r.Email); err != nil {
+			return err
+		}
+		if !r.SendAt.IsZero() && !r.SendAt.After(now) {
+			return fmt.Errorf("lettr: batch send time %s for %q is not in the future", r.SendAt.Format(time.RFC3339), r.Email)
+		}
+	}
+	return nil
+}
+
+// request builds the send request for a single recipient.
+func (b *SendBatchRequest) request(recipient BatchRecipient) *SendEmailRequest {
+	req := b.Email
+	req.To = []string{recipient.Email}
+	req.SubstitutionData = mergeStringMaps(b.Email.SubstitutionData, recipient.SubstitutionData)
+	req.Metadata = mergeStringMaps(b.Email.Metadata, recipient.Metadata)
+	return &req
+}
+
+// mergeStringMaps returns a new map holding base overlaid with override, or
+// nil if both are empty.
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
+
+// SendBatch sends params.Email to each of params.Recipients as a separate
+// email, at most Concurrency at a time, so every recipient gets their own
+// substitution data. Recipients with a SendAt are scheduled with Schedule
+// instead of sent immediately. Invalid batches return an error without
+// sending anything; failures of individual sends are reported in the result,
+// and also as a *BatchPartialError if ErrorOnFailure is set. Once ctx is done no further sends start, and the
+// remaining recipients fail with ctx's error. opts apply to every send, so
+// do not pass a fixed Idempotency-Key; use WithAutoIdempotency instead.
+//
+// Example:
+//
+//	result, err := client.Emails.SendBatch(ctx, &lettr.SendBatchRequest{
+//	    Email: lettr.SendEmailRequest{
+//	        From:         "news@example.com",
+//	        TemplateSlug: "newsletter",
+//	    },
+//	    Recipients: []lettr.BatchRecipient{
+//	        {Email: "a@example.com", SubstitutionData: map[string]string{"name": "Ann"}},
+//	        {Email: "b@example.com", SubstitutionData: map[string]string{"name": "Bob"}},
+//	    },
+//	})
+func (s *EmailService) SendBatch(ctx context.Context, params *SendBatchRequest, opts ...RequestOption) (*SendBatchResult, error) {
+	if params == nil {
+		return nil, fmt.Errorf("lettr: batch request is required")
+	}
+	if err := params.validate(s.client.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	concurrency := params.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]BatchRecipientResult, len(params.Recipients))
+	for i, recipient := range params.Recipients {
+		results[i].Email = recipient.Email
+	}
+	forEachConcurrently(ctx, len(params.Recipients), concurrency, func(i int) {
+		results[i].RequestID, results[i].Err = s.sendBatchRecipient(ctx, params, params.Recipients[i], opts)
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+
+	result := &SendBatchResult{Recipients: results}
+	for _, r := range results {
+		if r.Err != nil {
+			result.Failed++
+		} else {
+			result.Sent++
+		}
+	}
+	if params.ErrorOnFailure && result.Failed > 0 {
+		return result, &BatchPartialError{Result: result}
+	}
+	return result, nil
+}
+
+// sendBatchRecipient sends or schedules the email for a single recipient and
+// returns its transmission ID. A send the API accepts but rejects the
+// recipient of is reported as an error.
+func (s *EmailService) sendBatchRecipient(ctx context.Context, params *SendBatchRequest, recipient BatchRecipient, opts []RequestOption) (string, error) {
+	var requestID string
+	var accepted int
+	if recipient.SendAt.IsZero() {
+		resp, err := s.Send(ctx, params.request(recipient), opts...)
+		if err != nil {
+			return "", err
+		}
+		requestID, accepted = resp.Data.RequestID, resp.Data.Accepted
+	} else {
+		resp, err := s.Schedule(ctx, &ScheduleEmailRequest{
+			SendEmailRequest: *params.request(recipient),
+			ScheduledAt:      recipient.SendAt.UTC().Format(time.RFC3339),
+		}, opts...)
+		if err != nil {
+			return "", err
+		}
+		requestID, accepted = resp.Data.RequestID, resp.Data.Accepted
+	}
+	if accepted == 0 {
+		return requestID, fmt.Errorf("lettr: recipient %s was rejected", recipient.Email)
+	}
+	return requestID, nil
+}
+
+// WaitOptions controls how PollBatch polls.
+type WaitOptions struct {
+	// Interval is the wait between polls of each email (required).
+	Interval time.Duration
+
+	// Concurrency is the maximum number of emails polled at once
+	// (default 4).
+	Concurrency int
+}
+
+// terminalEventKinds are the event kinds after which an email's delivery
+// outcome no longer changes.
+var terminalEventKinds = map[string]bool{
+	"delivery":             true,
+	"bounce":               true,
+	"out_of_band":          true,
+	"policy_rejection":     true,
+	"generation_failure":   true,
+	"generation_rejection": true,
+}
+
+// PollErrors maps each request ID that did not reach a terminal event to the
+// error that stopped polling it (typically the context's error).
+type PollErrors map[string]error
+
+// Error implements the error interface.
+func (e PollErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var sb strings.Builder
+	sb.WriteString("lettr: emails not finished:")
+	for _, id := range ids {
+		sb.WriteString(fmt.Sprintf(" %s (%v);", id, e[id]))
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+// Unwrap returns the per-email errors so errors.Is and errors.As can match
+// any of them, e.g. context.DeadlineExceeded.
+func (e PollErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// PollBatch polls the emails with the given request IDs, such as those in a
+// SendBatchResult, until each reaches a terminal event (delivery, bounce,
+// rejection, ...) or ctx is done. It returns the terminal event for every
+// email that reached one and, if any did not, a PollErrors describing why.
+// When a poll reports the rate limit as exhausted, that email's next poll
+// waits for the limit to reset.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+//	defer cancel()
+//	events, err := client.Emails.PollBatch(ctx, ids, lettr.WaitOptions{Interval: 30 * time.Second})
+//	for id, event := range events {
+//	    log.Printf("%s: %s", id, event.Type)
+//	}
+func (s *EmailService) PollBatch(ctx context.Context, requestIDs []string, wait WaitOptions, opts ...RequestOption) (map[string]*EmailEvent, error) {
+	if err := validatePollInterval(wait.Interval); err != nil {
+		return nil, err
+	}
+	if wait.Concurrency < 0 {
+		return nil, fmt.Errorf("lettr: poll concurrency must not be negative, got %d", wait.Concurrency)
+	}
+	concurrency := wait.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]*EmailEvent, len(requestIDs))
+		errs    = PollErrors{}
+	)
+	forEachConcurrently(ctx, len(requestIDs), concurrency, func(i int) {
+		event, err := s.pollEmail(ctx, requestIDs[i], wait.Interval, opts)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[requestIDs[i]] = err
+			return
+		}
+		results[requestIDs[i]] = event
+	}, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[requestIDs[i]] = err
+	})
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
+
+// pollEmail polls a single email until it has a terminal event, returning
+// the latest one.
+func (s *EmailService) pollEmail(ctx context.Context, requestID string, interval time.Duration, opts []RequestOption) (*EmailEvent, error) {
+	for {
+		resp, err := s.Get(ctx, requestID, nil, opts...)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fmt.Errorf("lettr: stopped polling: %w", ctxErr)
+			}
+			return nil, err
+		}
+		for i := len(resp.Data.Events) - 1; i >= 0; i-- {
+			if event := resp.Data.Events[i]; terminalEventKinds[eventKind(event.Type)] {
+				return &event, nil
+			}
+		}
+
+		delay := interval
+		if rl := resp.RateLimit; rl != nil && rl.Remaining != nil && *rl.Remaining == 0 {
+			if untilReset := rl.Reset.Sub(s.client.clock.Now()); untilReset > delay {
+				delay = untilReset
+			}
+		}
+		if err := s.client.clock.Sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("lettr: stopped polling: %w", err)
+		}
+	}
+}
+
+// forEachConcurrently calls fn for every index in [0, n), at most limit at a
+// time, and waits for the calls to return. Once ctx is done no further calls
+// start; skipped is called with ctx's error for each index not started.
+func forEachConcurrently(ctx context.Context, n, limit int, fn func(i int), skipped func(i int, err error)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	skipRest := func(i int) {
+		for ; i < n; i++ {
+			skipped(i, ctx.Err())
+		}
+	}
+	for i := 0; i < n; i++ {
+		// Check ctx first: select picks at random when a slot is free and
+		// ctx is done at the same time.
+		if ctx.Err() != nil {
+			skipRest(i)
+			return
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			skipRest(i)
+			return
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+}
@@ -0,0 +1,94 @@
+package lettr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Campaign sends a template to a list of recipients, each with their own
+// substitution data. It covers the common "send template X to list Y"
+// orchestration on top of EmailService.SendBatch.
+type Campaign struct {
+	// TemplateSlug is the template to send (required).
+	TemplateSlug string
+
+	// TemplateVersion pins a specific template version (optional).
+	TemplateVersion *int
+
+	// ProjectID is the project to source the template from (optional).
+	ProjectID *int
+
+	// From is the sender email address (required).
+	From string
+
+	// FromName is the sender display name (optional).
+	FromName string
+
+	// Subject overrides the template's subject line (optional).
+	Subject string
+
+	// Recipients receive one email each (required).
+	Recipients []BatchRecipient
+
+	// Tag is applied to every email in the campaign (optional).
+	Tag string
+
+	// Options contains tracking and delivery options for every email.
+	Options *SendEmailOptions
+
+	// Concurrency is the maximum number of sends in flight (default 4).
+	Concurrency int
+}
+
+// CampaignResult reports the outcome of each recipient of a campaign, in
+// the order they were given. It is the SendBatchResult of the underlying
+// batch.
+type CampaignResult = SendBatchResult
+
+// validate checks the campaign-level fields; recipients are validated by
+// EmailService.SendBatch.
+func (c *Campaign) validate() error {
+	if c.TemplateSlug == "" {
+		return fmt.Errorf("lettr: campaign template slug is required")
+	}
+	if c.From == "" {
+		return fmt.Errorf("lettr: campaign sender is required")
+	}
+	return validateAddresses("from", c.From)
+}
+
+// Run validates the campaign and sends one email per recipient with
+// EmailService.SendBatch, at most Concurrency at a time. Recipients with a
+// SendAt are scheduled instead of sent immediately. Invalid campaigns return
+// an error without sending anything; failures of individual sends are
+// reported in the result rather than as an error.
+//
+// Example:
+//
+//	result, err := (&lettr.Campaign{
+//	    TemplateSlug: "spring-sale",
+//	    From:         "news@example.com",
+//	    Recipients: []lettr.BatchRecipient{
+//	        {Email: "a@example.com", SubstitutionData: map[string]string{"name": "Ann"}},
+//	        {Email: "b@example.com", SubstitutionData: map[string]string{"name": "Bob"}},
+//	    },
+//	}).Run(ctx, client)
+func (c *Campaign) Run(ctx context.Context, client *Client) (*CampaignResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return client.Emails.SendBatch(ctx, &SendBatchRequest{
+		Email: SendEmailRequest{
+			From:            c.From,
+			FromName:        c.FromName,
+			Subject:         c.Subject,
+			TemplateSlug:    c.TemplateSlug,
+			TemplateVersion: c.TemplateVersion,
+			ProjectID:       c.ProjectID,
+			Tag:             c.Tag,
+			Options:         c.Options,
+		},
+		Recipients:  c.Recipients,
+		Concurrency: c.Concurrency,
+	})
+}
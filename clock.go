@@ -0,0 +1,30 @@
+package lettr
+
+import (
+	"context"
+	"time"
+)
+
+// clock abstracts time so that waits can be faked in tests.
+type clock interface {
+	Now() time.Time
+
+	// Sleep waits for d, returning ctx.Err() early if ctx is done first.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is the clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
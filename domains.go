@@ -2,9 +2,14 @@ package lettr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // DomainService handles communication with the domain-related endpoints
@@ -65,14 +70,53 @@ type DnsProviderInfo struct {
 	Error         *string  `json:"error"`
 }
 
+// SetupStep is one item of a domain's onboarding checklist.
+type SetupStep struct {
+	// ID identifies the step: "dkim", "cname" or "can_send".
+	ID string
+
+	// Label is a human-readable description of the step.
+	Label string
+
+	// Done reports whether the step is complete.
+	Done bool
+}
+
+// dnsStatusValid is the DKIM/CNAME status the API reports once a record
+// has been verified.
+const dnsStatusValid = "valid"
+
+// SetupSteps returns the domain's onboarding checklist in the order the
+// steps are usually completed: DKIM verification, CNAME verification and
+// sending enabled.
+//
+// Example:
+//
+//	for _, step := range detail.SetupSteps() {
+//	    fmt.Printf("[%v] %s\n", step.Done, step.Label)
+//	}
+func (d *DomainDetail) SetupSteps() []SetupStep {
+	return []SetupStep{
+		{ID: "dkim", Label: "DKIM record verified", Done: d.DkimStatus != nil && *d.DkimStatus == dnsStatusValid},
+		{ID: "cname", Label: "CNAME record verified", Done: d.CnameStatus != nil && *d.CnameStatus == dnsStatusValid},
+		{ID: "can_send", Label: "Domain can send", Done: d.CanSend},
+	}
+}
+
 // CreateDomainRequest represents the request body for creating a domain.
 type CreateDomainRequest struct {
 	// Domain is the domain name to register (e.g. "example.com").
 	Domain string `json:"domain"`
+
+	// TrackingDomain sets the domain's click and open tracking domain in
+	// the same call (optional). It must be a subdomain of Domain, e.g.
+	// "track.example.com".
+	TrackingDomain string `json:"tracking_domain,omitempty"`
 }
 
 // ListDomainsResponse is the response from listing domains.
 type ListDomainsResponse struct {
+	ResponseMeta
 	Message string          `json:"message"`
 	Data    ListDomainsData `json:"data"`
 }
@@ -84,12 +128,14 @@ type ListDomainsData struct {
 
 // GetDomainResponse is the response from getting a single domain.
 type GetDomainResponse struct {
+	ResponseMeta
 	Message string       `json:"message"`
 	Data    DomainDetail `json:"data"`
 }
 
 // CreateDomainResponse is the response from creating a domain.
 type CreateDomainResponse struct {
+	ResponseMeta
 	Message string           `json:"message"`
 	Data    CreateDomainData `json:"data"`
 }
@@ -100,6 +146,9 @@ type CreateDomainData struct {
 	Status      string      `json:"status"`
 	StatusLabel string      `json:"status_label"`
 	DKIM        *DomainDKIM `json:"dkim"`
+
+	// TrackingDomain is the tracking domain set on create, if any.
+	TrackingDomain *string `json:"tracking_domain,omitempty"`
 }
 
 // List retrieves all sending domains registered with your account.
@@ -107,8 +156,8 @@ type CreateDomainData struct {
 // Example:
 //
 //	domains, err := client.Domains.List(ctx)
-func (s *DomainService) List(ctx context.Context) (*ListDomainsResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodGet, "domains", nil)
+func (s *DomainService) List(ctx context.Context, opts ...RequestOption) (*ListDomainsResponse, error) {
+	req, err := s.client.newRequest(ctx, "domains.list", http.MethodGet, "domains", nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -125,10 +174,10 @@ func (s *DomainService) List(ctx context.Context) (*ListDomainsResponse, error)
 // Example:
 //
 //	domain, err := client.Domains.Get(ctx, "example.com")
-func (s *DomainService) Get(ctx context.Context, domain string) (*GetDomainResponse, error) {
+func (s *DomainService) Get(ctx context.Context, domain string, opts ...RequestOption) (*GetDomainResponse, error) {
 	path := fmt.Sprintf("domains/%s", url.PathEscape(domain))
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "domains.get", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -140,16 +189,51 @@ func (s *DomainService) Get(ctx context.Context, domain string) (*GetDomainRespo
 	return &resp, nil
 }
 
+// GetDomainDNSResponse is the response from getting a domain's DNS records.
+type GetDomainDNSResponse struct {
+	ResponseMeta
+	Message string    `json:"message"`
+	Data    DomainDNS `json:"data"`
+}
+
+// DNS retrieves only the DNS records for a sending domain. It is a lighter
+// alternative to Get for DNS setup views.
+//
+// Example:
+//
+//	dns, err := client.Domains.DNS(ctx, "example.com")
+func (s *DomainService) DNS(ctx context.Context, domain string, opts ...RequestOption) (*DomainDNS, error) {
+	path := fmt.Sprintf("domains/%s/dns", url.PathEscape(domain))
+
+	req, err := s.client.newRequest(ctx, "domains.dns", http.MethodGet, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetDomainDNSResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
 // Create registers a new sending domain with your account.
-// The domain will start in a pending state until verified.
+// The domain will start in a pending state until verified. A TrackingDomain
+// is checked to be a subdomain of Domain before the request is sent.
 //
 // Example:
 //
 //	created, err := client.Domains.Create(ctx, &lettr.CreateDomainRequest{
 //	    Domain: "example.com",
 //	})
-func (s *DomainService) Create(ctx context.Context, params *CreateDomainRequest) (*CreateDomainResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodPost, "domains", params)
+func (s *DomainService) Create(ctx context.Context, params *CreateDomainRequest, opts ...RequestOption) (*CreateDomainResponse, error) {
+	if params != nil && params.TrackingDomain != "" {
+		if err := validateTrackingDomain(params.TrackingDomain, params.Domain); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := s.client.newRequest(ctx, "domains.create", http.MethodPost, "domains", params, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -161,16 +245,28 @@ func (s *DomainService) Create(ctx context.Context, params *CreateDomainRequest)
 	return &resp, nil
 }
 
+// validateTrackingDomain checks that tracking is a valid hostname and a
+// subdomain of domain.
+func validateTrackingDomain(tracking, domain string) error {
+	if err := validateHostname(tracking); err != nil {
+		return fmt.Errorf("lettr: invalid tracking domain: %w", err)
+	}
+	if !strings.HasSuffix(strings.ToLower(tracking), "."+strings.ToLower(domain)) {
+		return fmt.Errorf("lettr: tracking domain %q is not a subdomain of %q", tracking, domain)
+	}
+	return nil
+}
+
 // Delete removes a sending domain. The domain will no longer be available
 // for sending emails.
 //
 // Example:
 //
 //	err := client.Domains.Delete(ctx, "example.com")
-func (s *DomainService) Delete(ctx context.Context, domain string) error {
+func (s *DomainService) Delete(ctx context.Context, domain string, opts ...RequestOption) error {
 	path := fmt.Sprintf("domains/%s", url.PathEscape(domain))
 
-	req, err := s.client.newRequest(ctx, http.MethodDelete, path, nil)
+	req, err := s.client.newRequest(ctx, "domains.delete", http.MethodDelete, path, nil, opts...)
 	if err != nil {
 		return err
 	}
@@ -179,8 +275,93 @@ func (s *DomainService) Delete(ctx context.Context, domain string) error {
 	return err
 }
 
+// UpdateDomainRequest represents the request body for updating a domain.
+// Nil fields are left unchanged.
+type UpdateDomainRequest struct {
+	// TrackingDomain sets the domain's click and open tracking domain. An
+	// empty string clears it.
+	TrackingDomain *string `json:"tracking_domain,omitempty"`
+
+	// CanSend pauses (false) or resumes (true) sending from the domain.
+	CanSend *bool `json:"can_send,omitempty"`
+}
+
+// Update changes a domain's settings and returns the updated domain. A
+// TrackingDomain is checked to be a subdomain of domain before the request
+// is sent.
+//
+// Example:
+//
+//	tracking := "track.example.com"
+//	resp, err := client.Domains.Update(ctx, "example.com", &lettr.UpdateDomainRequest{
+//	    TrackingDomain: &tracking,
+//	})
+func (s *DomainService) Update(ctx context.Context, domain string, params *UpdateDomainRequest, opts ...RequestOption) (*GetDomainResponse, error) {
+	if params != nil && params.TrackingDomain != nil && *params.TrackingDomain != "" {
+		if err := validateTrackingDomain(*params.TrackingDomain, domain); err != nil {
+			return nil, err
+		}
+	}
+	path := fmt.Sprintf("domains/%s", url.PathEscape(domain))
+
+	req, err := s.client.newRequest(ctx, "domains.update", http.MethodPatch, path, params, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetDomainResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetSendingEnabled pauses or resumes sending from a domain without deleting
+// it, so its history and DNS setup are kept.
+//
+// Example:
+//
+//	resp, err := client.Domains.SetSendingEnabled(ctx, "example.com", false)
+func (s *DomainService) SetSendingEnabled(ctx context.Context, domain string, enabled bool, opts ...RequestOption) (*GetDomainResponse, error) {
+	return s.Update(ctx, domain, &UpdateDomainRequest{CanSend: &enabled}, opts...)
+}
+
+// RotateDKIMResponse is the response from rotating a domain's DKIM key.
+type RotateDKIMResponse struct {
+	ResponseMeta
+	Message string     `json:"message"`
+	Data    DomainDKIM `json:"data"`
+}
+
+// RotateDKIM generates a new DKIM key for domain and returns the selector
+// and public key to publish in DNS. Lettr keeps signing with the previous
+// key until the new record verifies, so mail is not affected while DNS
+// propagates.
+//
+// Example:
+//
+//	dkim, err := client.Domains.RotateDKIM(ctx, "example.com")
+//	if err == nil {
+//	    fmt.Printf("publish %s._domainkey: v=DKIM1; k=rsa; p=%s\n", dkim.Selector, dkim.Public)
+//	}
+func (s *DomainService) RotateDKIM(ctx context.Context, domain string, opts ...RequestOption) (*DomainDKIM, error) {
+	path := fmt.Sprintf("domains/%s/dkim/rotate", url.PathEscape(domain))
+
+	req, err := s.client.newRequest(ctx, "domains.rotate_dkim", http.MethodPost, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RotateDKIMResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
 // VerifyDomainResponse is the response from verifying a domain.
 type VerifyDomainResponse struct {
+	ResponseMeta
 	Message string                 `json:"message"`
 	Data    DomainVerificationView `json:"data"`
 }
@@ -237,10 +418,10 @@ type DomainDnsVerificationView struct {
 // Example:
 //
 //	result, err := client.Domains.Verify(ctx, "example.com")
-func (s *DomainService) Verify(ctx context.Context, domain string) (*VerifyDomainResponse, error) {
+func (s *DomainService) Verify(ctx context.Context, domain string, opts ...RequestOption) (*VerifyDomainResponse, error) {
 	path := fmt.Sprintf("domains/%s/verify", url.PathEscape(domain))
 
-	req, err := s.client.newRequest(ctx, http.MethodPost, path, nil)
+	req, err := s.client.newRequest(ctx, "domains.verify", http.MethodPost, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -251,3 +432,215 @@ func (s *DomainService) Verify(ctx context.Context, domain string) (*VerifyDomai
 	}
 	return &resp, nil
 }
+
+// ErrNoSends is returned by rate helpers when no emails were sent in the
+// requested window, so no meaningful rate can be computed.
+var ErrNoSends = errors.New("lettr: no emails sent in the requested window")
+
+// DomainStatsParams contains the query parameters for getting domain stats.
+type DomainStatsParams struct {
+	// From is the start of the reporting window (ISO 8601).
+	From string
+
+	// To is the end of the reporting window (ISO 8601).
+	To string
+}
+
+// DomainStatsResponse is the response from getting domain stats.
+type DomainStatsResponse struct {
+	ResponseMeta
+	Message string      `json:"message"`
+	Data    DomainStats `json:"data"`
+}
+
+// DomainStats contains aggregate sending totals for a domain over a window.
+type DomainStats struct {
+	Domain    string `json:"domain"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Sent      int    `json:"sent"`
+	Delivered int    `json:"delivered"`
+	Bounced   int    `json:"bounced"`
+}
+
+// Stats retrieves aggregate sending totals for a domain.
+//
+// Pass nil for params to use the API's default window.
+//
+// Example:
+//
+//	stats, err := client.Domains.Stats(ctx, "example.com", &lettr.DomainStatsParams{
+//	    From: "2024-01-01",
+//	    To:   "2024-01-31",
+//	})
+func (s *DomainService) Stats(ctx context.Context, domain string, params *DomainStatsParams, opts ...RequestOption) (*DomainStatsResponse, error) {
+	path := fmt.Sprintf("domains/%s/stats", url.PathEscape(domain))
+	if params != nil {
+		q := url.Values{}
+		if params.From != "" {
+			q.Set("from", params.From)
+		}
+		if params.To != "" {
+			q.Set("to", params.To)
+		}
+		if encoded := q.Encode(); encoded != "" {
+			path += "?" + encoded
+		}
+	}
+
+	req, err := s.client.newRequest(ctx, "domains.stats", http.MethodGet, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DomainStatsResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BounceRate returns the fraction (0-1) of emails sent from domain between
+// from and to that bounced. It returns an error wrapping ErrNoSends if
+// nothing was sent in the window.
+//
+// Example:
+//
+//	rate, err := client.Domains.BounceRate(ctx, "example.com", time.Now().Add(-24*time.Hour), time.Now())
+func (s *DomainService) BounceRate(ctx context.Context, domain string, from, to time.Time, opts ...RequestOption) (float64, error) {
+	if from.After(to) {
+		return 0, fmt.Errorf("lettr: from (%s) is after to (%s)",
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	stats, err := s.Stats(ctx, domain, &DomainStatsParams{
+		From: from.UTC().Format(time.RFC3339),
+		To:   to.UTC().Format(time.RFC3339),
+	}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	if stats.Data.Sent == 0 {
+		return 0, fmt.Errorf("%w (domain %s)", ErrNoSends, domain)
+	}
+	return float64(stats.Data.Bounced) / float64(stats.Data.Sent), nil
+}
+
+// DomainWaitErrors maps each domain that did not become verified to the
+// error that stopped polling it (typically the context's error).
+type DomainWaitErrors map[string]error
+
+// Error implements the error interface.
+func (e DomainWaitErrors) Error() string {
+	domains := make([]string, 0, len(e))
+	for domain := range e {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	var sb strings.Builder
+	sb.WriteString("lettr: domains not verified:")
+	for _, domain := range domains {
+		sb.WriteString(fmt.Sprintf(" %s (%v);", domain, e[domain]))
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+// Unwrap returns the per-domain errors so errors.Is and errors.As can match
+// any of them, e.g. context.DeadlineExceeded.
+func (e DomainWaitErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// WaitUntilAllVerified polls every domain concurrently, once per interval,
+// until each one can send or ctx is done. It returns the last fetched detail
+// for every domain (nil if none could be fetched) and, if any domain did not
+// become verified, a DomainWaitErrors describing why. interval must be
+// positive.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+//	defer cancel()
+//	details, err := client.Domains.WaitUntilAllVerified(ctx, []string{"a.com", "b.com"}, 30*time.Second)
+func (s *DomainService) WaitUntilAllVerified(ctx context.Context, domains []string, interval time.Duration, opts ...RequestOption) (map[string]*DomainDetail, error) {
+	if err := validatePollInterval(interval); err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]*DomainDetail, len(domains))
+		errs    = DomainWaitErrors{}
+	)
+	for _, domain := range domains {
+		wg.Add(1)
+		go func(domain string) {
+			defer wg.Done()
+			detail, err := s.WaitUntilVerified(ctx, domain, interval, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[domain] = detail
+			if err != nil {
+				errs[domain] = err
+			}
+		}(domain)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
+
+// WaitUntilVerified polls domain once per interval until it can send or ctx
+// is done. It returns the last fetched detail alongside any error that
+// stopped polling; if ctx ends first, the error wraps ctx.Err(), so
+// errors.Is(err, context.DeadlineExceeded) reports a timeout. interval must
+// be positive.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+//	defer cancel()
+//	detail, err := client.Domains.WaitUntilVerified(ctx, "example.com", 30*time.Second)
+func (s *DomainService) WaitUntilVerified(ctx context.Context, domain string, interval time.Duration, opts ...RequestOption) (*DomainDetail, error) {
+	if err := validatePollInterval(interval); err != nil {
+		return nil, err
+	}
+
+	var last *DomainDetail
+	for {
+		resp, err := s.Get(ctx, domain, opts...)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return last, fmt.Errorf("lettr: stopped waiting for verification: %w", ctxErr)
+			}
+			return last, err
+		}
+		last = &resp.Data
+		if last.CanSend {
+			return last, nil
+		}
+
+		if err := s.client.clock.Sleep(ctx, interval); err != nil {
+			return last, fmt.Errorf("lettr: stopped waiting for verification: %w", err)
+		}
+	}
+}
+
+// validatePollInterval rejects intervals that would poll the API in a tight
+// loop.
+func validatePollInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("lettr: poll interval must be positive, got %v", interval)
+	}
+	return nil
+}
@@ -2,11 +2,15 @@ package lettr
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // EmailService handles communication with the email-related endpoints
@@ -41,10 +45,16 @@ type SendEmailRequest struct {
 	// Text is the plain text body content. At least one of Html or Text is required.
 	Text string `json:"text,omitempty"`
 
+	// Charset is the character set of Html and Text, e.g. "ISO-8859-1"
+	// (optional). It is omitted when empty, and the server uses UTF-8.
+	Charset string `json:"charset,omitempty"`
+
 	// AmpHtml is the AMP HTML content for supported email clients (optional).
 	AmpHtml string `json:"amp_html,omitempty"`
 
-	// ReplyTo is the reply-to email address (optional).
+	// ReplyTo is the reply-to email address (optional), e.g. a support desk
+	// when From is a no-reply address. The API accepts a single address; it
+	// is omitted from the request body when empty.
 	ReplyTo string `json:"reply_to,omitempty"`
 
 	// ReplyToName is the reply-to display name (optional).
@@ -62,6 +72,10 @@ type SendEmailRequest struct {
 	// Attachments is a list of file attachments (base64-encoded).
 	Attachments []Attachment `json:"attachments,omitempty"`
 
+	// AttachmentRefs references attachments uploaded ahead of time with
+	// EmailService.UploadAttachment.
+	AttachmentRefs []string `json:"attachment_refs,omitempty"`
+
 	// SubstitutionData contains key-value pairs for template variable replacement.
 	SubstitutionData map[string]string `json:"substitution_data,omitempty"`
 
@@ -71,11 +85,42 @@ type SendEmailRequest struct {
 	// Tag is a tag for tracking and analytics (optional).
 	Tag string `json:"tag,omitempty"`
 
-	// Headers contains custom email headers (up to 10, optional).
+	// Headers contains custom email headers (up to 10, optional), e.g.
+	// X-Campaign-ID or List-Unsubscribe. Names are sent exactly as given.
+	// Headers the API derives from other fields (From, To, Subject, etc.)
+	// are rejected before sending.
 	Headers map[string]string `json:"headers,omitempty"`
 
 	// Options contains tracking and delivery options.
 	Options *SendEmailOptions `json:"options,omitempty"`
+
+	// SendAt delays delivery until the given time, which must be in the
+	// future (optional). It is sent as an RFC 3339 timestamp in UTC. Use
+	// EmailService.Schedule instead to get a transmission that can be
+	// looked up or cancelled; Schedule rejects requests that set SendAt.
+	SendAt *time.Time `json:"send_at,omitempty"`
+}
+
+// idempotencyKeyHeader is the request header the API uses to deduplicate sends.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKey returns a deterministic key derived from the request's
+// canonical JSON encoding. Identical requests yield identical keys, while any
+// difference in content yields a different key. Options.Priority is not part
+// of the encoding; the keys attached by WithAutoIdempotency are computed after
+// it has been mapped to headers, so they do distinguish priorities.
+func (r *SendEmailRequest) IdempotencyKey() string {
+	return hashJSON(r)
+}
+
+// hashJSON returns the hex-encoded SHA-256 of v's JSON encoding. Struct fields
+// are encoded in declaration order and map keys are sorted, so the encoding
+// is stable for equal values.
+func hashJSON(v interface{}) string {
+	// Request types contain only JSON-safe values, so Marshal cannot fail.
+	b, _ := json.Marshal(v)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
 }
 
 // SendEmailOptions contains optional send settings.
@@ -94,6 +139,32 @@ type SendEmailOptions struct {
 
 	// PerformSubstitutions enables variable substitutions in content.
 	PerformSubstitutions *bool `json:"perform_substitutions,omitempty"`
+
+	// TrackingDomain is a branded tracking domain to use for this send
+	// instead of the account default. It must be a tracking domain
+	// configured on your account; the SDK only checks that it is a
+	// well-formed host name.
+	TrackingDomain string `json:"tracking_domain,omitempty"`
+
+	// Priority flags the message as PriorityHigh, PriorityNormal or
+	// PriorityLow. It is not sent as an option; the SDK maps it to the
+	// X-Priority and Importance email headers, overriding any values for
+	// those headers in SendEmailRequest.Headers.
+	Priority string `json:"-"`
+}
+
+// Message priorities for SendEmailOptions.Priority.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// priorityHeaders maps each priority to its X-Priority and Importance values.
+var priorityHeaders = map[string][2]string{
+	PriorityHigh:   {"1 (Highest)", "high"},
+	PriorityNormal: {"3 (Normal)", "normal"},
+	PriorityLow:    {"5 (Lowest)", "low"},
 }
 
 // Attachment represents a file attachment on an email.
@@ -104,12 +175,37 @@ type Attachment struct {
 	// Type is the MIME type of the attachment (e.g. "application/pdf").
 	Type string `json:"type"`
 
-	// Data is the base64-encoded content of the attachment.
-	Data string `json:"data"`
+	// Data is the base64-encoded content of the attachment. Exactly one of
+	// Data or PresignedURL must be set.
+	Data string `json:"data,omitempty"`
+
+	// PresignedURL is a time-limited URL (e.g. an S3 or GCS presigned URL)
+	// that Lettr fetches the attachment from instead of receiving it inline.
+	PresignedURL string `json:"presigned_url,omitempty"`
+
+	// Checksum optionally lets Lettr verify content fetched from
+	// PresignedURL, in "algorithm:hex" form (e.g. "sha256:9f86d0...").
+	Checksum string `json:"checksum,omitempty"`
+
+	// Disposition is DispositionAttachment (the default when empty) or
+	// DispositionInline for content embedded in the HTML body.
+	Disposition string `json:"disposition,omitempty"`
+
+	// ContentID identifies an inline attachment, so the HTML body can
+	// reference it as "cid:<ContentID>", e.g. <img src="cid:logo">. Only
+	// meaningful when Disposition is DispositionInline.
+	ContentID string `json:"content_id,omitempty"`
 }
 
+// Attachment dispositions for Attachment.Disposition.
+const (
+	DispositionAttachment = "attachment"
+	DispositionInline     = "inline"
+)
+
 // SendEmailResponse is the response from sending an email.
 type SendEmailResponse struct {
+	ResponseMeta
 	Message string        `json:"message"`
 	Data    SendEmailData `json:"data"`
 }
@@ -181,6 +277,83 @@ type EmailEvent struct {
 	UserAgentParsed       *UserAgentParsed       `json:"user_agent_parsed,omitempty"`
 	GeoIp                 *GeoIp                 `json:"geo_ip,omitempty"`
 	IpAddress             *string                `json:"ip_address,omitempty"`
+
+	// Click, Bounce and Open hold the type-specific details of click,
+	// bounce and open events respectively; the others are nil. They are
+	// populated when decoding and duplicate the corresponding flat fields.
+	Click  *ClickDetails  `json:"-"`
+	Bounce *BounceDetails `json:"-"`
+	Open   *OpenDetails   `json:"-"`
+
+	// Raw is the event's original JSON, including any fields the SDK does
+	// not model.
+	Raw json.RawMessage `json:"-"`
+}
+
+// ClickDetails contains the details specific to click events.
+type ClickDetails struct {
+	TargetLinkURL   string           `json:"target_link_url"`
+	TargetLinkName  string           `json:"target_link_name"`
+	UserAgent       string           `json:"user_agent"`
+	UserAgentParsed *UserAgentParsed `json:"user_agent_parsed"`
+	GeoIp           *GeoIp           `json:"geo_ip"`
+	IpAddress       string           `json:"ip_address"`
+}
+
+// BounceDetails contains the details specific to bounce events.
+type BounceDetails struct {
+	Reason      string `json:"reason"`
+	RawReason   string `json:"raw_reason"`
+	ErrorCode   string `json:"error_code"`
+	BounceClass int    `json:"bounce_class"`
+}
+
+// OpenDetails contains the details specific to open events.
+type OpenDetails struct {
+	InitialPixel    bool             `json:"initial_pixel"`
+	UserAgent       string           `json:"user_agent"`
+	UserAgentParsed *UserAgentParsed `json:"user_agent_parsed"`
+	GeoIp           *GeoIp           `json:"geo_ip"`
+	IpAddress       string           `json:"ip_address"`
+}
+
+// UnmarshalJSON decodes an event and, based on its type, fills in Click,
+// Bounce or Open. Both plain ("click") and namespaced ("engagement.click")
+// type names are recognised.
+func (e *EmailEvent) UnmarshalJSON(data []byte) error {
+	type emailEvent EmailEvent // drops the method to avoid recursion
+	var ev emailEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return err
+	}
+	*e = EmailEvent(ev)
+	e.Raw = append(json.RawMessage(nil), data...)
+	return e.decodeDetails(data)
+}
+
+// decodeDetails fills in Click, Bounce or Open from data based on e.Type.
+func (e *EmailEvent) decodeDetails(data []byte) error {
+	var details interface{}
+	switch eventKind(e.Type) {
+	case "click", "amp_click":
+		e.Click = &ClickDetails{}
+		details = e.Click
+	case "bounce", "out_of_band":
+		e.Bounce = &BounceDetails{}
+		details = e.Bounce
+	case "open", "initial_open", "amp_open", "amp_initial_open":
+		e.Open = &OpenDetails{}
+		details = e.Open
+	default:
+		return nil
+	}
+	return json.Unmarshal(data, details)
+}
+
+// eventKind strips any namespace from an event type, so "engagement.click"
+// and "click" both yield "click".
+func eventKind(eventType string) string {
+	return eventType[strings.LastIndex(eventType, ".")+1:]
 }
 
 // UserAgentParsed contains parsed user agent information from open/click events.
@@ -208,7 +381,9 @@ type GeoIp struct {
 
 // ListEmailsParams contains the query parameters for listing emails.
 type ListEmailsParams struct {
-	// PerPage is the number of results per page (1-100, default 25).
+	// PerPage is the number of results per page (1-100, default 25). Zero
+	// uses the default; values outside the range are rejected before the
+	// request is sent.
 	PerPage int
 
 	// Cursor is the pagination cursor from a previous response.
@@ -222,10 +397,71 @@ type ListEmailsParams struct {
 
 	// To filters emails sent on or before this date (ISO 8601, e.g. "2024-01-31").
 	To string
+
+	// Search is a full-text query matched by the server against subjects and
+	// recipients. It combines with the date and recipient filters above.
+	Search string
+
+	// SendingIP filters by the IP address the email was sent from, e.g. to
+	// monitor IP warm-up. Matches EmailEvent.SendingIP.
+	SendingIP string
+
+	// Extra holds additional query parameters for filters the SDK does not
+	// model yet. Parameters set by the fields above take precedence over
+	// extras with the same name.
+	Extra url.Values
+}
+
+// Merge returns a new ListEmailsParams combining p and other. Fields set
+// (non-zero) in other take precedence; zero fields in other keep p's value.
+// Extra is merged per key, again preferring other. Either side may be nil,
+// and neither is modified.
+//
+// Example:
+//
+//	params := baseFilter.Merge(&lettr.ListEmailsParams{Recipients: userInput})
+func (p *ListEmailsParams) Merge(other *ListEmailsParams) *ListEmailsParams {
+	merged := ListEmailsParams{}
+	if p != nil {
+		merged = *p
+	}
+	if other == nil {
+		return &merged
+	}
+	if other.PerPage != 0 {
+		merged.PerPage = other.PerPage
+	}
+	if other.Cursor != "" {
+		merged.Cursor = other.Cursor
+	}
+	if other.Recipients != "" {
+		merged.Recipients = other.Recipients
+	}
+	if other.From != "" {
+		merged.From = other.From
+	}
+	if other.To != "" {
+		merged.To = other.To
+	}
+	if other.Search != "" {
+		merged.Search = other.Search
+	}
+	if other.SendingIP != "" {
+		merged.SendingIP = other.SendingIP
+	}
+	if len(other.Extra) > 0 {
+		extra := cloneValues(merged.Extra)
+		for key, values := range other.Extra {
+			extra[key] = append([]string(nil), values...)
+		}
+		merged.Extra = extra
+	}
+	return &merged
 }
 
 // ListEmailsResponse is the response from listing emails.
 type ListEmailsResponse struct {
+	ResponseMeta
 	Message string         `json:"message"`
 	Data    ListEmailsData `json:"data"`
 }
@@ -249,12 +485,152 @@ type ListEmailsEvents struct {
 type CursorPagination struct {
 	NextCursor *string `json:"next_cursor"`
 	PerPage    int     `json:"per_page"`
+
+	// Total is the number of items across all pages, or nil if the API did
+	// not report it. Unlike the page's TotalCount, it does not change as you
+	// page through the results.
+	Total *int `json:"total,omitempty"`
+}
+
+// HasNext reports whether another page is available. A missing or
+// zero-value pagination object, or an empty cursor, means there is none.
+func (p CursorPagination) HasNext() bool {
+	return p.NextCursor != nil && *p.NextCursor != ""
+}
+
+// NextParams returns a copy of params positioned at the next page, or nil if
+// this was the last page. Callers can loop until NextParams returns nil.
+//
+// Example:
+//
+//	params := &lettr.ListEmailsParams{PerPage: 100}
+//	for params != nil {
+//	    page, err := client.Emails.List(ctx, params)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // ...
+//	    params = page.NextParams(params)
+//	}
+func (r *ListEmailsResponse) NextParams(params *ListEmailsParams) *ListEmailsParams {
+	next := ListEmailsParams{}
+	if params != nil {
+		next = *params
+	}
+	if !advanceCursor(r.Data.Events.Pagination, &next.Cursor) {
+		return nil
+	}
+	return &next
+}
+
+// EmailIterator walks every email matching a ListEmailsParams, fetching
+// pages as needed. Create one with EmailService.ListAll.
+type EmailIterator struct {
+	ctx     context.Context
+	service *EmailService
+	params  *ListEmailsParams
+	opts    []RequestOption
+	page    []EmailEvent
+	current *EmailEvent
+	err     error
+}
+
+// ListAll returns an iterator over all sent emails matching params,
+// following the pagination cursor until the last page. PerPage and the
+// filters in params apply to every page. Pass nil for params to use defaults.
+//
+// Example:
+//
+//	it := client.Emails.ListAll(ctx, &lettr.ListEmailsParams{PerPage: 100})
+//	for it.Next() {
+//	    fmt.Println(it.Event().RequestID)
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+func (s *EmailService) ListAll(ctx context.Context, params *ListEmailsParams, opts ...RequestOption) *EmailIterator {
+	first := ListEmailsParams{}
+	if params != nil {
+		first = *params
+	}
+	return &EmailIterator{ctx: ctx, service: s, params: &first, opts: opts}
+}
+
+// Next advances to the next email, fetching the next page when the current
+// one is exhausted. It returns false when there are no more emails, the
+// context is cancelled, or a request fails; check Err afterwards.
+func (it *EmailIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || it.params == nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		resp, err := it.service.List(it.ctx, it.params, it.opts...)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = resp.Data.Events.Data
+		it.params = resp.NextParams(it.params)
+	}
+	it.current = &it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Event returns the email at the iterator's current position.
+func (it *EmailIterator) Event() *EmailEvent {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EmailIterator) Err() error {
+	return it.err
+}
+
+// NextParams returns a copy of params positioned at the next page, or nil if
+// this was the last page.
+func (r *ListEmailEventsResponse) NextParams(params *ListEmailEventsParams) *ListEmailEventsParams {
+	next := ListEmailEventsParams{}
+	if params != nil {
+		next = *params
+	}
+	if !advanceCursor(r.Data.Events.Pagination, &next.Cursor) {
+		return nil
+	}
+	return &next
+}
+
+// advanceCursor moves *cursor to the next page's cursor. It reports false
+// when there is no next page, including when the server repeats the current
+// cursor, which would otherwise loop forever.
+func advanceCursor(p CursorPagination, cursor *string) bool {
+	if !p.HasNext() || *p.NextCursor == *cursor {
+		return false
+	}
+	*cursor = *p.NextCursor
+	return true
+}
+
+// cloneValues returns a deep copy of v, or empty values if v is nil. List
+// methods start from a copy of the caller's extra parameters and then set
+// the known ones, so known parameters win on conflict.
+func cloneValues(v url.Values) url.Values {
+	out := make(url.Values, len(v))
+	for key, values := range v {
+		out[key] = append([]string(nil), values...)
+	}
+	return out
 }
 
 // GetEmailResponse is the response from getting email details.
 // The data shape matches ShowScheduledTransmissionResponse — transmission
 // metadata plus the full list of delivery events.
 type GetEmailResponse struct {
+	ResponseMeta
 	Message string                `json:"message"`
 	Data    ScheduledTransmission `json:"data"`
 }
@@ -269,12 +645,20 @@ type GetEmailResponse struct {
 //	    Subject: "Hello from Lettr",
 //	    Html:    "<h1>Hello!</h1>",
 //	})
-func (s *EmailService) Send(ctx context.Context, params *SendEmailRequest) (*SendEmailResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodPost, "emails", params)
+func (s *EmailService) Send(ctx context.Context, params *SendEmailRequest, opts ...RequestOption) (*SendEmailResponse, error) {
+	body, err := s.prepare(params)
 	if err != nil {
 		return nil, err
 	}
 
+	req, err := s.client.newRequest(ctx, "emails.send", http.MethodPost, "emails", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.client.autoIdempotency && req.Header.Get(idempotencyKeyHeader) == "" {
+		req.Header.Set(idempotencyKeyHeader, body.IdempotencyKey())
+	}
+
 	var resp SendEmailResponse
 	if _, err := s.client.do(req, &resp); err != nil {
 		return nil, err
@@ -282,6 +666,151 @@ func (s *EmailService) Send(ctx context.Context, params *SendEmailRequest) (*Sen
 	return &resp, nil
 }
 
+// SendWithIdempotencyKey is like Send but sends key, unchanged, as the
+// Idempotency-Key header, so that application-level retries with the same
+// key are delivered at most once. An explicit key takes precedence over one
+// generated by WithAutoIdempotency.
+//
+// Example:
+//
+//	resp, err := client.Emails.SendWithIdempotencyKey(ctx, params, "order-1234-receipt")
+func (s *EmailService) SendWithIdempotencyKey(ctx context.Context, params *SendEmailRequest, key string, opts ...RequestOption) (*SendEmailResponse, error) {
+	if key == "" {
+		return nil, fmt.Errorf("lettr: idempotency key must not be empty")
+	}
+	return s.Send(ctx, params, append(opts, WithHeader(idempotencyKeyHeader, key))...)
+}
+
+// SendWithTimeout is like Send but bounds the call by timeout instead of a
+// caller-supplied context. Prefer Send when a context is already available,
+// so cancellation propagates from the caller.
+//
+// Example:
+//
+//	resp, err := client.Emails.SendWithTimeout(5*time.Second, params)
+func (s *EmailService) SendWithTimeout(timeout time.Duration, params *SendEmailRequest, opts ...RequestOption) (*SendEmailResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.Send(ctx, params, opts...)
+}
+
+// Inbox placements reported by SeedTest.
+const (
+	PlacementInbox   = "inbox"
+	PlacementSpam    = "spam"
+	PlacementMissing = "missing"
+)
+
+// seedTestRequest is the request body for SeedTest.
+type seedTestRequest struct {
+	*SendEmailRequest
+	SeedListID string `json:"seed_list_id"`
+}
+
+// SeedTestResponse is the response from a seed test.
+type SeedTestResponse struct {
+	ResponseMeta
+	Message string       `json:"message"`
+	Data    SeedTestData `json:"data"`
+}
+
+// SeedTestData contains where the test email landed for each seed inbox.
+type SeedTestData struct {
+	SeedListID string          `json:"seed_list_id"`
+	Results    []SeedPlacement `json:"results"`
+}
+
+// SeedPlacement is the placement of a seed test email in one seed inbox.
+type SeedPlacement struct {
+	// Seed is the seed inbox's address.
+	Seed string `json:"seed"`
+
+	// Provider is the mailbox provider hosting the seed (e.g. "gmail").
+	Provider string `json:"provider"`
+
+	// Placement is one of the Placement* constants.
+	Placement string `json:"placement"`
+}
+
+// SeedTest sends params to every inbox of the seed list seedListID instead of
+// its recipients, and reports whether each copy landed in the inbox or spam
+// folder. Use it to check deliverability before a campaign. The recipients in
+// params are still validated but are not mailed.
+//
+// Example:
+//
+//	resp, err := client.Emails.SeedTest(ctx, params, "seed-list-1")
+//	for _, r := range resp.Data.Results {
+//	    if r.Placement != lettr.PlacementInbox {
+//	        log.Printf("%s (%s): %s", r.Seed, r.Provider, r.Placement)
+//	    }
+//	}
+func (s *EmailService) SeedTest(ctx context.Context, params *SendEmailRequest, seedListID string, opts ...RequestOption) (*SeedTestResponse, error) {
+	if seedListID == "" {
+		return nil, fmt.Errorf("lettr: seed list ID must not be empty")
+	}
+	body, err := s.prepare(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.newRequest(ctx, "emails.seed_test", http.MethodPost, "emails/seed-test", &seedTestRequest{SendEmailRequest: body, SeedListID: seedListID}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SeedTestResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// prepare validates params and returns the request body to send, with
+// SDK-level settings such as Options.Priority and recipient normalization
+// applied. params itself is never modified.
+func (s *EmailService) prepare(params *SendEmailRequest) (*SendEmailRequest, error) {
+	if params == nil {
+		return nil, nil
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if err := params.validateSubstitutionSize(s.client.maxSubstitutionBytes); err != nil {
+		return nil, err
+	}
+	if err := params.validateAttachmentSize(s.client.maxAttachmentBytes, s.client.maxTotalAttachmentBytes); err != nil {
+		return nil, err
+	}
+	if err := s.client.validateRecipientCount(params); err != nil {
+		return nil, err
+	}
+	if params.SendAt != nil && !params.SendAt.After(s.client.clock.Now()) {
+		return nil, fmt.Errorf("lettr: send time %s is not in the future", params.SendAt.Format(time.RFC3339))
+	}
+
+	body := *params
+	if params.SendAt != nil {
+		sendAt := params.SendAt.UTC().Truncate(time.Second)
+		body.SendAt = &sendAt
+	}
+	if s.client.normalizeRecipients {
+		body.To = bareAddresses(params.To)
+		body.Cc = bareAddresses(params.Cc)
+		body.Bcc = bareAddresses(params.Bcc)
+	}
+	if params.Options != nil && params.Options.Priority != "" {
+		values := priorityHeaders[params.Options.Priority]
+		body.Headers = make(map[string]string, len(params.Headers)+2)
+		for k, v := range params.Headers {
+			body.Headers[k] = v
+		}
+		body.Headers["X-Priority"] = values[0]
+		body.Headers["Importance"] = values[1]
+	}
+	return &body, nil
+}
+
 // List retrieves a paginated list of sent emails.
 //
 // Pass nil for params to use defaults.
@@ -291,10 +820,13 @@ func (s *EmailService) Send(ctx context.Context, params *SendEmailRequest) (*Sen
 //	emails, err := client.Emails.List(ctx, &lettr.ListEmailsParams{
 //	    PerPage: 10,
 //	})
-func (s *EmailService) List(ctx context.Context, params *ListEmailsParams) (*ListEmailsResponse, error) {
+func (s *EmailService) List(ctx context.Context, params *ListEmailsParams, opts ...RequestOption) (*ListEmailsResponse, error) {
 	path := "emails"
 	if params != nil {
-		q := url.Values{}
+		if err := validatePerPage(params.PerPage); err != nil {
+			return nil, err
+		}
+		q := cloneValues(params.Extra)
 		if params.PerPage > 0 {
 			q.Set("per_page", strconv.Itoa(params.PerPage))
 		}
@@ -310,12 +842,18 @@ func (s *EmailService) List(ctx context.Context, params *ListEmailsParams) (*Lis
 		if params.To != "" {
 			q.Set("to", params.To)
 		}
+		if params.Search != "" {
+			q.Set("q", params.Search)
+		}
+		if params.SendingIP != "" {
+			q.Set("sending_ip", params.SendingIP)
+		}
 		if encoded := q.Encode(); encoded != "" {
 			path += "?" + encoded
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "emails.list", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -342,7 +880,7 @@ type GetEmailParams struct {
 // Example:
 //
 //	details, err := client.Emails.Get(ctx, "12345678901234567890", nil)
-func (s *EmailService) Get(ctx context.Context, requestID string, params *GetEmailParams) (*GetEmailResponse, error) {
+func (s *EmailService) Get(ctx context.Context, requestID string, params *GetEmailParams, opts ...RequestOption) (*GetEmailResponse, error) {
 	path := fmt.Sprintf("emails/%s", url.PathEscape(requestID))
 	if params != nil {
 		q := url.Values{}
@@ -357,7 +895,7 @@ func (s *EmailService) Get(ctx context.Context, requestID string, params *GetEma
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "emails.get", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -369,6 +907,118 @@ func (s *EmailService) Get(ctx context.Context, requestID string, params *GetEma
 	return &resp, nil
 }
 
+// TrackingInfo describes the tracking applied to a sent email.
+type TrackingInfo struct {
+	// OpenTracking reports whether an open tracking pixel was injected.
+	OpenTracking bool `json:"open_tracking"`
+
+	// ClickTracking reports whether links were rewritten for click tracking.
+	ClickTracking bool `json:"click_tracking"`
+
+	// OpenTrackingPixelURL is the URL of the injected tracking pixel, if the
+	// API provides it.
+	OpenTrackingPixelURL *string `json:"open_tracking_pixel_url"`
+
+	// RewrittenLinkCount is the number of links rewritten for click tracking.
+	RewrittenLinkCount int `json:"rewritten_link_count"`
+}
+
+// TrackingInfoResponse is the response from getting an email's tracking info.
+type TrackingInfoResponse struct {
+	ResponseMeta
+	Message string       `json:"message"`
+	Data    TrackingInfo `json:"data"`
+}
+
+// TrackingInfo retrieves the open and click tracking applied to a sent
+// email, which helps debug why opens or clicks are not being recorded.
+//
+// Example:
+//
+//	info, err := client.Emails.TrackingInfo(ctx, "request-id")
+//	if err == nil && !info.OpenTracking {
+//	    log.Println("no tracking pixel was injected")
+//	}
+func (s *EmailService) TrackingInfo(ctx context.Context, requestID string, opts ...RequestOption) (*TrackingInfo, error) {
+	path := fmt.Sprintf("emails/%s/tracking", url.PathEscape(requestID))
+
+	req, err := s.client.newRequest(ctx, "emails.tracking_info", http.MethodGet, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TrackingInfoResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// ProviderStats contains delivery totals for a single mailbox provider.
+type ProviderStats struct {
+	Sent      int `json:"sent"`
+	Delivered int `json:"delivered"`
+	Bounced   int `json:"bounced"`
+}
+
+// DeliveryRate returns the fraction (0-1) of sent emails that were
+// delivered, or 0 if nothing was sent.
+func (p ProviderStats) DeliveryRate() float64 {
+	if p.Sent == 0 {
+		return 0
+	}
+	return float64(p.Delivered) / float64(p.Sent)
+}
+
+// ProviderBreakdownResponse is the response from getting the mailbox
+// provider breakdown.
+type ProviderBreakdownResponse struct {
+	ResponseMeta
+	Message string                `json:"message"`
+	Data    ProviderBreakdownData `json:"data"`
+}
+
+// ProviderBreakdownData contains delivery totals keyed by mailbox provider,
+// using the same names as EmailEvent.MailboxProvider.
+type ProviderBreakdownData struct {
+	Providers map[string]ProviderStats `json:"providers"`
+}
+
+// ProviderBreakdown retrieves delivery totals between from and to, split by
+// mailbox provider (Gmail, Outlook, Yahoo, ...).
+//
+// Example:
+//
+//	providers, err := client.Emails.ProviderBreakdown(ctx, time.Now().AddDate(0, 0, -7), time.Now())
+//	for name, stats := range providers {
+//	    fmt.Printf("%s: %.1f%% delivered\n", name, stats.DeliveryRate()*100)
+//	}
+func (s *EmailService) ProviderBreakdown(ctx context.Context, from, to time.Time, opts ...RequestOption) (map[string]ProviderStats, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("lettr: from (%s) is after to (%s)",
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	q := url.Values{}
+	q.Set("from", from.UTC().Format(time.RFC3339))
+	q.Set("to", to.UTC().Format(time.RFC3339))
+	path := "emails/stats/providers?" + q.Encode()
+
+	req, err := s.client.newRequest(ctx, "emails.provider_breakdown", http.MethodGet, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ProviderBreakdownResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Providers == nil {
+		return map[string]ProviderStats{}, nil
+	}
+	return resp.Data.Providers, nil
+}
+
 // ListEmailEventsParams contains the query parameters for listing email events.
 type ListEmailEventsParams struct {
 	// Events filters by event types (e.g. "delivery", "bounce", "open", "click").
@@ -398,6 +1048,7 @@ type ListEmailEventsParams struct {
 
 // ListEmailEventsResponse is the response from listing email events.
 type ListEmailEventsResponse struct {
+	ResponseMeta
 	Message string              `json:"message"`
 	Data    ListEmailEventsData `json:"data"`
 }
@@ -428,7 +1079,7 @@ type ListEmailEventsEvents struct {
 //	    Events:  []string{"delivery", "bounce"},
 //	    PerPage: 50,
 //	})
-func (s *EmailService) ListEvents(ctx context.Context, params *ListEmailEventsParams) (*ListEmailEventsResponse, error) {
+func (s *EmailService) ListEvents(ctx context.Context, params *ListEmailEventsParams, opts ...RequestOption) (*ListEmailEventsResponse, error) {
 	path := "emails/events"
 	if params != nil {
 		q := url.Values{}
@@ -465,7 +1116,7 @@ func (s *EmailService) ListEvents(ctx context.Context, params *ListEmailEventsPa
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "emails.list_events", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -487,8 +1138,15 @@ type ScheduleEmailRequest struct {
 	ScheduledAt string `json:"scheduled_at"`
 }
 
+// IdempotencyKey returns a deterministic key derived from the full scheduling
+// request, including ScheduledAt.
+func (r *ScheduleEmailRequest) IdempotencyKey() string {
+	return hashJSON(r)
+}
+
 // ScheduleEmailResponse is the response from scheduling an email.
 type ScheduleEmailResponse struct {
+	ResponseMeta
 	Message string            `json:"message"`
 	Data    ScheduleEmailData `json:"data"`
 }
@@ -507,6 +1165,7 @@ type ScheduleEmailData struct {
 
 // GetScheduledEmailResponse is the response from getting a scheduled email.
 type GetScheduledEmailResponse struct {
+	ResponseMeta
 	Message string                 `json:"message"`
 	Data    ScheduledTransmission  `json:"data"`
 }
@@ -537,11 +1196,26 @@ type ScheduledTransmission struct {
 //	    },
 //	    ScheduledAt: "2024-12-25T10:00:00Z",
 //	})
-func (s *EmailService) Schedule(ctx context.Context, params *ScheduleEmailRequest) (*ScheduleEmailResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodPost, "emails/scheduled", params)
+func (s *EmailService) Schedule(ctx context.Context, params *ScheduleEmailRequest, opts ...RequestOption) (*ScheduleEmailResponse, error) {
+	body := params
+	if params != nil {
+		if params.SendAt != nil {
+			return nil, fmt.Errorf("lettr: scheduled emails must use ScheduledAt, not SendAt")
+		}
+		prepared, err := s.prepare(&params.SendEmailRequest)
+		if err != nil {
+			return nil, err
+		}
+		body = &ScheduleEmailRequest{SendEmailRequest: *prepared, ScheduledAt: params.ScheduledAt}
+	}
+
+	req, err := s.client.newRequest(ctx, "emails.schedule", http.MethodPost, "emails/scheduled", body, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if s.client.autoIdempotency && req.Header.Get(idempotencyKeyHeader) == "" {
+		req.Header.Set(idempotencyKeyHeader, body.IdempotencyKey())
+	}
 
 	var resp ScheduleEmailResponse
 	if _, err := s.client.do(req, &resp); err != nil {
@@ -555,10 +1229,10 @@ func (s *EmailService) Schedule(ctx context.Context, params *ScheduleEmailReques
 // Example:
 //
 //	scheduled, err := client.Emails.GetScheduled(ctx, "transmission-123")
-func (s *EmailService) GetScheduled(ctx context.Context, transmissionID string) (*GetScheduledEmailResponse, error) {
+func (s *EmailService) GetScheduled(ctx context.Context, transmissionID string, opts ...RequestOption) (*GetScheduledEmailResponse, error) {
 	path := fmt.Sprintf("emails/scheduled/%s", url.PathEscape(transmissionID))
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "emails.get_scheduled", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -572,6 +1246,7 @@ func (s *EmailService) GetScheduled(ctx context.Context, transmissionID string)
 
 // CancelScheduledResponse is the response from cancelling a scheduled email.
 type CancelScheduledResponse struct {
+	ResponseMeta
 	Message string `json:"message"`
 }
 
@@ -580,10 +1255,10 @@ type CancelScheduledResponse struct {
 // Example:
 //
 //	resp, err := client.Emails.CancelScheduled(ctx, "transmission-123")
-func (s *EmailService) CancelScheduled(ctx context.Context, transmissionID string) (*CancelScheduledResponse, error) {
+func (s *EmailService) CancelScheduled(ctx context.Context, transmissionID string, opts ...RequestOption) (*CancelScheduledResponse, error) {
 	path := fmt.Sprintf("emails/scheduled/%s", url.PathEscape(transmissionID))
 
-	req, err := s.client.newRequest(ctx, http.MethodDelete, path, nil)
+	req, err := s.client.newRequest(ctx, "emails.cancel_scheduled", http.MethodDelete, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3,8 +3,10 @@ package lettr
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // Error represents an error returned by the Lettr API.
@@ -20,6 +22,15 @@ type Error struct {
 
 	// Errors contains field-level validation errors (for 422 responses).
 	Errors map[string][]string `json:"errors,omitempty"`
+
+	// MaxSize is the largest accepted request size in bytes, when the API
+	// reports one alongside a 413 Payload Too Large response.
+	MaxSize int64 `json:"max_size,omitempty"`
+
+	// RetryAfter is how long the API asked the caller to wait before
+	// retrying, from the Retry-After header of a 429 or 503 response. It is
+	// zero when the header is absent.
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error implements the error interface.
@@ -29,6 +40,9 @@ func (e *Error) Error() string {
 	if e.ErrorCode != "" {
 		sb.WriteString(fmt.Sprintf(" (code: %s)", e.ErrorCode))
 	}
+	if e.MaxSize > 0 {
+		sb.WriteString(fmt.Sprintf(" (max size: %d bytes)", e.MaxSize))
+	}
 	if len(e.Errors) > 0 {
 		for field, msgs := range e.Errors {
 			for _, msg := range msgs {
@@ -39,6 +53,18 @@ func (e *Error) Error() string {
 	return sb.String()
 }
 
+// ToFieldMap returns the first validation message for each field, for
+// binding 422 errors to form inputs. The full messages remain in Errors.
+func (e *Error) ToFieldMap() map[string]string {
+	fields := make(map[string]string, len(e.Errors))
+	for field, msgs := range e.Errors {
+		if len(msgs) > 0 {
+			fields[field] = msgs[0]
+		}
+	}
+	return fields
+}
+
 // IsNotFound returns true if the error is a 404 Not Found error.
 func IsNotFound(err error) bool {
 	if e, ok := err.(*Error); ok {
@@ -63,19 +89,48 @@ func IsUnauthorized(err error) bool {
 	return false
 }
 
-// parseError reads the response body and constructs an *Error.
-func parseError(resp *http.Response) error {
+// IsPayloadTooLarge returns true if the error is a 413 Payload Too Large
+// error. Error.MaxSize carries the server's limit when it reports one.
+func IsPayloadTooLarge(err error) bool {
+	if e, ok := err.(*Error); ok {
+		return e.StatusCode == http.StatusRequestEntityTooLarge
+	}
+	return false
+}
+
+// IsRateLimited returns true if the error is a 429 Too Many Requests error.
+// Error.RetryAfter carries the wait the API asked for, if any.
+func IsRateLimited(err error) bool {
+	if e, ok := err.(*Error); ok {
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// maxErrorBodyBytes bounds how much of an error response body is read.
+const maxErrorBodyBytes = 1 << 20
+
+// parseError reads the response body and constructs an *Error. If the body
+// is not a JSON error and an error body snippet is configured, part of the
+// body is appended to the status text so that non-JSON errors (e.g. an HTML
+// 502 page from a proxy) can be diagnosed.
+func (c *Client) parseError(resp *http.Response) error {
 	apiErr := &Error{
 		StatusCode: resp.StatusCode,
 	}
+	apiErr.RetryAfter, _ = parseRetryAfter(resp.Header.Get(retryAfterHeader), c.clock.Now())
 
 	if resp.Body == nil {
 		apiErr.Message = http.StatusText(resp.StatusCode)
 		return apiErr
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if err := json.Unmarshal(body, apiErr); err != nil {
 		apiErr.Message = http.StatusText(resp.StatusCode)
+		if snippet := bodySnippet(body, c.errorBodySnippet); snippet != "" {
+			apiErr.Message += ": " + snippet
+		}
 	}
 
 	if apiErr.Message == "" {
@@ -84,3 +139,16 @@ func parseError(resp *http.Response) error {
 
 	return apiErr
 }
+
+// bodySnippet returns body with whitespace collapsed, truncated to at most
+// n bytes (plus an ellipsis), or "" if n is not positive or body is blank.
+func bodySnippet(body []byte, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if len(text) <= n {
+		return text
+	}
+	return strings.ToValidUTF8(text[:n], "") + "…"
+}
@@ -0,0 +1,77 @@
+package lettr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// InboundEmail is an email received by Lettr and forwarded to an inbound
+// webhook, e.g. a customer's reply.
+type InboundEmail struct {
+	// MessageID is the Message-ID header of the received email.
+	MessageID string `json:"message_id"`
+
+	// From is the sender address, possibly with a display name.
+	From string `json:"from"`
+
+	// To, Cc and ReplyTo are the addresses from the corresponding headers.
+	To      []string `json:"to"`
+	Cc      []string `json:"cc,omitempty"`
+	ReplyTo string   `json:"reply_to,omitempty"`
+
+	// Subject is the email's subject line.
+	Subject string `json:"subject"`
+
+	// Text and Html are the plain-text and HTML bodies; either may be empty.
+	Text string `json:"text,omitempty"`
+	Html string `json:"html,omitempty"`
+
+	// Headers contains the email's headers, keyed by name.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Attachments holds the email's attachments, decoded.
+	Attachments []InboundAttachment `json:"attachments,omitempty"`
+
+	// ReceivedAt is when Lettr received the email (ISO 8601).
+	ReceivedAt string `json:"received_at"`
+}
+
+// InboundAttachment is an attachment of an InboundEmail.
+type InboundAttachment struct {
+	// Name is the attachment's filename.
+	Name string `json:"name"`
+
+	// Type is the MIME type of the attachment (e.g. "application/pdf").
+	Type string `json:"type"`
+
+	// ContentID identifies inline attachments referenced from the HTML body.
+	ContentID string `json:"content_id,omitempty"`
+
+	// Content is the attachment's content, decoded from the payload's
+	// base64 data.
+	Content []byte `json:"data"`
+}
+
+// ParseInboundEmail decodes the body of an inbound email webhook delivery.
+// Verify the delivery against WebhookService.SigningKey before trusting its
+// contents.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	email, err := lettr.ParseInboundEmail(body)
+//	if err != nil {
+//	    http.Error(w, "bad payload", http.StatusBadRequest)
+//	    return
+//	}
+//	createTicket(email.From, email.Subject, email.Text)
+func ParseInboundEmail(body []byte) (*InboundEmail, error) {
+	var email InboundEmail
+	if err := json.Unmarshal(body, &email); err != nil {
+		return nil, fmt.Errorf("lettr: failed to decode inbound email: %w", err)
+	}
+	if email.From == "" {
+		return nil, fmt.Errorf("lettr: inbound email has no sender")
+	}
+	return &email, nil
+}
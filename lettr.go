@@ -20,7 +20,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -28,6 +30,19 @@ const (
 	// Version is the current version of this SDK.
 	Version = "1.1.0"
 
+	// DefaultMaxSubstitutionBytes is the default cap on the combined
+	// serialized size of a send's SubstitutionData and Metadata.
+	DefaultMaxSubstitutionBytes = 64 << 10
+
+	// MaxAttachmentBytes is the API's cap on the decoded size of a single
+	// inline attachment. Use WithMaxAttachmentBytes if your plan allows more.
+	MaxAttachmentBytes = 10 << 20
+
+	// MaxTotalAttachmentBytes is the API's cap on the combined decoded size
+	// of a send's inline attachments. Use WithMaxTotalAttachmentBytes if
+	// your plan allows more.
+	MaxTotalAttachmentBytes = 20 << 20
+
 	defaultBaseURL = "https://app.lettr.com/api/"
 	userAgent      = "lettr-go/" + Version
 	contentType    = "application/json"
@@ -47,6 +62,53 @@ type Client struct {
 	// userAgent is the User-Agent header sent with each request.
 	userAgent string
 
+	// apiVersion is the X-API-Version header sent with each request, or
+	// empty to send none.
+	apiVersion string
+
+	// acceptLanguage is the Accept-Language header sent with each request,
+	// if set.
+	acceptLanguage string
+
+	// autoIdempotency attaches content-derived Idempotency-Key headers to sends.
+	autoIdempotency bool
+
+	// normalizeRecipients reduces display-name recipients to bare addresses.
+	normalizeRecipients bool
+
+	// errorBodySnippet is how many bytes of a non-JSON error body to include
+	// in Error.Message; zero disables snippets.
+	errorBodySnippet int
+
+	// maxSubstitutionBytes caps the serialized size of a send's
+	// SubstitutionData and Metadata.
+	maxSubstitutionBytes int
+
+	// maxAttachmentBytes and maxTotalAttachmentBytes cap the decoded size
+	// of each inline attachment and of all of a send's inline attachments.
+	maxAttachmentBytes      int64
+	maxTotalAttachmentBytes int64
+
+	// onRequest and onResponse observe each HTTP attempt; nil disables them.
+	onRequest  func(*http.Request)
+	onResponse func(*http.Response)
+
+	// retry controls automatic retries; the zero value disables them.
+	retry RetryConfig
+
+	// retryBudget caps the total retry wait across calls; nil means no cap.
+	retryBudget *retryBudget
+
+	// retryObserver is called before each retry wait, if set.
+	retryObserver func(attempt int, err error, nextDelay time.Duration)
+
+	// limitsMu guards limits, which caches the result of Limits.
+	limitsMu sync.Mutex
+	limits   *AccountLimits
+
+	// clock is used for retry and polling waits and is replaced in tests.
+	clock clock
+
 	// Services for different API resources.
 	Emails    *EmailService
 	Domains   *DomainService
@@ -77,6 +139,11 @@ func NewClientWithHTTPClient(apiKey string, httpClient *http.Client) *Client {
 		apiKey:     strings.TrimSpace(apiKey),
 		baseURL:    baseURL,
 		userAgent:  userAgent,
+		clock:      realClock{},
+
+		maxSubstitutionBytes:    DefaultMaxSubstitutionBytes,
+		maxAttachmentBytes:      MaxAttachmentBytes,
+		maxTotalAttachmentBytes: MaxTotalAttachmentBytes,
 	}
 
 	c.Emails = &EmailService{client: c}
@@ -102,8 +169,123 @@ func (c *Client) SetBaseURL(rawURL string) error {
 	return nil
 }
 
-// newRequest builds an HTTP request for the Lettr API.
-func (c *Client) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+// ClientConfig is a point-in-time snapshot of a client's effective
+// configuration, safe to log or print. The API key is redacted.
+type ClientConfig struct {
+	// BaseURL is the base URL requests are sent to.
+	BaseURL string
+
+	// APIKey is the redacted API key; only the last four characters are kept.
+	APIKey string
+
+	// UserAgent is the User-Agent header sent with each request.
+	UserAgent string
+
+	// APIVersion is the X-API-Version header sent with each request, or
+	// empty if WithAPIVersion was not used and no header is sent.
+	APIVersion string
+
+	// AcceptLanguage is the Accept-Language header sent with each request,
+	// or empty if none is sent.
+	AcceptLanguage string
+
+	// Timeout is the HTTP client timeout (zero means no timeout).
+	Timeout time.Duration
+
+	// AutoIdempotency reports whether sends carry content-derived
+	// Idempotency-Key headers.
+	AutoIdempotency bool
+
+	// NormalizeRecipients reports whether display-name recipients are
+	// reduced to bare addresses before sending.
+	NormalizeRecipients bool
+
+	// ErrorBodySnippet is how many bytes of a non-JSON error body are
+	// included in error messages (zero means none).
+	ErrorBodySnippet int
+
+	// MaxSubstitutionBytes is the cap on the combined serialized size of a
+	// send's SubstitutionData and Metadata.
+	MaxSubstitutionBytes int
+
+	// MaxAttachmentBytes is the cap on the decoded size of a single inline
+	// attachment.
+	MaxAttachmentBytes int64
+
+	// MaxTotalAttachmentBytes is the cap on the combined decoded size of a
+	// send's inline attachments.
+	MaxTotalAttachmentBytes int64
+
+	// Retry is the automatic retry configuration; MaxAttempts of 1 or less
+	// means retries are disabled.
+	Retry RetryConfig
+
+	// RetryBudget is the total time the client may spend waiting between
+	// retries across all calls (zero means no limit).
+	RetryBudget time.Duration
+}
+
+// Config returns a redacted snapshot of the client's effective configuration,
+// intended for debugging and support tickets.
+func (c *Client) Config() ClientConfig {
+	return ClientConfig{
+		BaseURL:                 c.baseURL.String(),
+		APIKey:                  redactAPIKey(c.apiKey),
+		UserAgent:               c.userAgent,
+		APIVersion:              c.apiVersion,
+		AcceptLanguage:          c.acceptLanguage,
+		Timeout:                 c.httpClient.Timeout,
+		AutoIdempotency:         c.autoIdempotency,
+		NormalizeRecipients:     c.normalizeRecipients,
+		ErrorBodySnippet:        c.errorBodySnippet,
+		MaxSubstitutionBytes:    c.maxSubstitutionBytes,
+		MaxAttachmentBytes:      c.maxAttachmentBytes,
+		MaxTotalAttachmentBytes: c.maxTotalAttachmentBytes,
+		Retry:                   c.retry,
+		RetryBudget:             c.retryBudgetTotal(),
+	}
+}
+
+// retryBudgetTotal returns the configured retry budget, or zero if none.
+func (c *Client) retryBudgetTotal() time.Duration {
+	if c.retryBudget == nil {
+		return 0
+	}
+	return c.retryBudget.total
+}
+
+// redactAPIKey masks all but the last four characters of key. Keys too short
+// to keep a suffix without revealing most of the secret are fully masked.
+func redactAPIKey(key string) string {
+	const visible = 4
+	if len(key) <= 2*visible {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", len(key)-visible) + key[len(key)-visible:]
+}
+
+// operationKey is the context key for the operation name of a request.
+type operationKey struct{}
+
+// OperationName returns the SDK operation that issued the request carrying
+// ctx, e.g. "emails.send" or "domains.verify", or "" if there is none. It is
+// meant for instrumented transports (see WithTransport) that name spans or
+// metrics after the call rather than the URL:
+//
+//	func (t *tracingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+//	    ctx, span := tracer.Start(r.Context(), lettr.OperationName(r.Context()))
+//	    defer span.End()
+//	    return t.next.RoundTrip(r.WithContext(ctx))
+//	}
+func OperationName(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+// newRequest builds an HTTP request for the Lettr API, applying any
+// per-call options after the default headers have been set. op names the
+// calling operation for OperationName.
+func (c *Client) newRequest(ctx context.Context, op, method, path string, body interface{}, opts ...RequestOption) (*http.Request, error) {
 	u, err := c.baseURL.Parse(path)
 	if err != nil {
 		return nil, fmt.Errorf("lettr: invalid path %q: %w", path, err)
@@ -118,6 +300,7 @@ func (c *Client) newRequest(ctx context.Context, method, path string, body inter
 		buf = bytes.NewReader(b)
 	}
 
+	ctx = context.WithValue(ctx, operationKey{}, op)
 	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
 	if err != nil {
 		return nil, err
@@ -126,25 +309,109 @@ func (c *Client) newRequest(ctx context.Context, method, path string, body inter
 	req.Header.Set("Accept", contentType)
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.apiVersion != "" {
+		req.Header.Set(apiVersionHeader, c.apiVersion)
+	}
+	if c.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+	}
 
 	if body != nil {
 		req.Header.Set("Content-Type", contentType)
 	}
 
+	for _, opt := range opts {
+		if err := opt(req); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
-// do sends an HTTP request and decodes the JSON response into v.
-// It returns the raw HTTP response and any error encountered.
+// apiVersionHeader pins the API version a request is served with.
+const apiVersionHeader = "X-API-Version"
+
+// Response headers read into ResponseMeta.
+const (
+	requestIDHeader          = "X-Request-ID"
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// ResponseMeta holds details about the HTTP response that are not part of
+// the JSON body. It is embedded in every response type.
+type ResponseMeta struct {
+	// RequestID is the API's correlation ID for the call, taken from the
+	// X-Request-ID response header. Include it when contacting support. It
+	// is unrelated to the transmission IDs returned by email sends.
+	RequestID string `json:"-"`
+
+	// RateLimit is the rate limit state reported with the response, or nil
+	// if the API sent no rate limit headers.
+	RateLimit *RateLimit `json:"-"`
+}
+
+// RateLimit is the API rate limit state after a request, from the
+// X-RateLimit-* response headers. Use it to throttle before hitting 429s.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window, or nil
+	// if the API did not say.
+	Limit *int
+
+	// Remaining is the number of requests left in the current window, or
+	// nil if the API did not say.
+	Remaining *int
+
+	// Reset is when the current window ends, or the zero time if the API
+	// did not say.
+	Reset time.Time
+}
+
+// setResponseMeta fills m from resp.
+func (m *ResponseMeta) setResponseMeta(resp *http.Response) {
+	m.RequestID = resp.Header.Get(requestIDHeader)
+	m.RateLimit = parseRateLimit(resp.Header)
+}
+
+// parseRateLimit reads the rate limit headers from h, returning nil if
+// neither the limit nor the remaining count is present. Only fields whose
+// headers parse are set. Reset is a Unix timestamp in seconds.
+func parseRateLimit(h http.Header) *RateLimit {
+	rl := &RateLimit{}
+	if limit, err := strconv.Atoi(h.Get(rateLimitLimitHeader)); err == nil {
+		rl.Limit = &limit
+	}
+	if remaining, err := strconv.Atoi(h.Get(rateLimitRemainingHeader)); err == nil {
+		rl.Remaining = &remaining
+	}
+	if rl.Limit == nil && rl.Remaining == nil {
+		return nil
+	}
+	if reset, err := strconv.ParseInt(h.Get(rateLimitResetHeader), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl
+}
+
+// responseMetaSetter is implemented by response types embedding ResponseMeta.
+type responseMetaSetter interface {
+	setResponseMeta(resp *http.Response)
+}
+
+// do sends an HTTP request, retrying transient failures if configured, and
+// decodes the JSON response into v. It returns the raw HTTP response and any
+// error encountered.
 func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		return nil, fmt.Errorf("lettr: request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return resp, parseError(resp)
+		return resp, c.parseError(resp)
 	}
 
 	if v != nil && resp.StatusCode != http.StatusNoContent {
@@ -152,13 +419,43 @@ func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
 			return resp, fmt.Errorf("lettr: failed to decode response: %w", err)
 		}
 	}
+	if m, ok := v.(responseMetaSetter); ok {
+		m.setResponseMeta(resp)
+	}
 
 	return resp, nil
 }
 
+// observeRequest passes a copy of req to the OnRequest hook, if any, so the
+// hook can neither consume the body nor change the headers actually sent.
+// The copy's API key is redacted.
+func (c *Client) observeRequest(req *http.Request) {
+	if c.onRequest == nil {
+		return
+	}
+	r := req.Clone(req.Context())
+	r.Body = http.NoBody
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		r.Header.Set("Authorization", "Bearer "+redactAPIKey(strings.TrimPrefix(auth, "Bearer ")))
+	}
+	c.onRequest(r)
+}
+
+// observeResponse passes a copy of resp without its body to the OnResponse
+// hook, if any.
+func (c *Client) observeResponse(resp *http.Response) {
+	if c.onResponse == nil {
+		return
+	}
+	r := *resp
+	r.Header = resp.Header.Clone()
+	r.Body = http.NoBody
+	c.onResponse(&r)
+}
+
 // HealthCheck verifies that the Lettr API is reachable.
-func (c *Client) HealthCheck(ctx context.Context) (*HealthCheckResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "health", nil)
+func (c *Client) HealthCheck(ctx context.Context, opts ...RequestOption) (*HealthCheckResponse, error) {
+	req, err := c.newRequest(ctx, "client.health_check", http.MethodGet, "health", nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -173,10 +470,40 @@ func (c *Client) HealthCheck(ctx context.Context) (*HealthCheckResponse, error)
 	return &resp, nil
 }
 
+// Service statuses reported by Status.
+const (
+	StatusOperational = "operational"
+	StatusDegraded    = "degraded"
+	StatusMaintenance = "maintenance"
+)
+
+// Status retrieves Lettr's announced service status. Unlike HealthCheck,
+// which only confirms the API is reachable, it reflects declared incidents
+// and maintenance windows.
+//
+// Example:
+//
+//	status, err := client.Status(ctx)
+//	if err == nil && status.Data.Status != lettr.StatusOperational {
+//	    log.Printf("Lettr is %s: %s", status.Data.Status, status.Data.Message)
+//	}
+func (c *Client) Status(ctx context.Context, opts ...RequestOption) (*StatusResponse, error) {
+	req, err := c.newRequest(ctx, "client.status", http.MethodGet, "status", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp StatusResponse
+	if _, err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // ValidateAPIKey checks whether the configured API key is valid and returns
 // the associated team information.
-func (c *Client) ValidateAPIKey(ctx context.Context) (*AuthCheckResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "auth/check", nil)
+func (c *Client) ValidateAPIKey(ctx context.Context, opts ...RequestOption) (*AuthCheckResponse, error) {
+	req, err := c.newRequest(ctx, "client.validate_api_key", http.MethodGet, "auth/check", nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -188,8 +515,35 @@ func (c *Client) ValidateAPIKey(ctx context.Context) (*AuthCheckResponse, error)
 	return &resp, nil
 }
 
+// HasScope reports whether the configured API key grants scope. Unscoped
+// keys, for which the API reports no scopes, have full access and always
+// return true.
+//
+// Example:
+//
+//	ok, err := client.HasScope(ctx, "emails:send")
+//	if err != nil || !ok {
+//	    log.Fatal("API key cannot send email")
+//	}
+func (c *Client) HasScope(ctx context.Context, scope string, opts ...RequestOption) (bool, error) {
+	resp, err := c.ValidateAPIKey(ctx, opts...)
+	if err != nil {
+		return false, err
+	}
+	if resp.Data.Scopes == nil {
+		return true, nil
+	}
+	for _, s := range resp.Data.Scopes {
+		if s == scope {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // HealthCheckResponse is the response from the health check endpoint.
 type HealthCheckResponse struct {
+	ResponseMeta
 	Message string          `json:"message"`
 	Data    HealthCheckData `json:"data"`
 }
@@ -200,8 +554,28 @@ type HealthCheckData struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// StatusResponse is the response from the status endpoint.
+type StatusResponse struct {
+	ResponseMeta
+	Message string     `json:"message"`
+	Data    StatusData `json:"data"`
+}
+
+// StatusData contains the announced service status.
+type StatusData struct {
+	// Status is StatusOperational, StatusDegraded or StatusMaintenance.
+	Status string `json:"status"`
+
+	// Message describes the incident or maintenance, if any.
+	Message string `json:"message,omitempty"`
+
+	// UpdatedAt is when the status last changed (ISO 8601).
+	UpdatedAt string `json:"updated_at"`
+}
+
 // AuthCheckResponse is the response from the auth check endpoint.
 type AuthCheckResponse struct {
+	ResponseMeta
 	Message string        `json:"message"`
 	Data    AuthCheckData `json:"data"`
 }
@@ -210,4 +584,8 @@ type AuthCheckResponse struct {
 type AuthCheckData struct {
 	TeamID    int    `json:"team_id"`
 	Timestamp string `json:"timestamp"`
+
+	// Scopes lists the permissions granted to a scoped API key (e.g.
+	// "emails:send"). It is nil for unscoped keys, which have full access.
+	Scopes []string `json:"scopes,omitempty"`
 }
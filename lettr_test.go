@@ -3,11 +3,31 @@ package lettr
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/mail"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 func strPtr(s string) *string { return &s }
@@ -423,6 +443,29 @@ func TestNotFoundError(t *testing.T) {
 	}
 }
 
+func TestPayloadTooLargeError(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(`{"message":"Payload too large.","error_code":"payload_too_large","max_size":10485760}`))
+	})
+	defer server.Close()
+
+	_, err := client.Emails.Send(context.Background(), &SendEmailRequest{})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !IsPayloadTooLarge(err) {
+		t.Errorf("expected payload too large error, got: %v", err)
+	}
+	if apiErr := err.(*Error); apiErr.MaxSize != 10485760 {
+		t.Errorf("expected max size 10485760, got %d", apiErr.MaxSize)
+	}
+	if !strings.Contains(err.Error(), "max size: 10485760 bytes") {
+		t.Errorf("expected max size in message, got %q", err.Error())
+	}
+}
+
 func TestUserAgentHeader(t *testing.T) {
 	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
 		ua := r.Header.Get("User-Agent")
@@ -846,6 +889,51 @@ func TestGetTemplate(t *testing.T) {
 	}
 }
 
+func TestGetTemplateMergeTags(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/templates/spring%2Fsale" {
+			t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Template retrieved.","data":{"id":4,"slug":"spring/sale","active_version":3,"merge_tags":[{"key":"name","required":true},{"key":"items","required":false,"type":"loop","children":[{"key":"title"}]}]}}`))
+	})
+	defer server.Close()
+
+	resp, err := client.Templates.Get(context.Background(), "spring/sale", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []MergeTag{
+		{Key: "name", Required: true},
+		{Key: "items", Type: "loop", Children: []MergeTagChild{{Key: "title"}}},
+	}
+	if !reflect.DeepEqual(resp.Data.MergeTags, want) {
+		t.Errorf("expected merge tags %+v, got %+v", want, resp.Data.MergeTags)
+	}
+	if resp.Data.ActiveVersion == nil || *resp.Data.ActiveVersion != 3 {
+		t.Errorf("expected active version 3, got %v", resp.Data.ActiveVersion)
+	}
+}
+
+func TestTemplateHtmlAndJsonConflict(t *testing.T) {
+	requests := 0
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := client.Templates.Create(ctx, &CreateTemplateRequest{Name: "Welcome", Html: "<h1>Hi</h1>", Json: "{}"}); err == nil {
+		t.Error("expected error creating a template with Html and Json")
+	}
+	if _, err := client.Templates.Update(ctx, "welcome", &UpdateTemplateRequest{Html: "<h1>Hi</h1>", Json: "{}"}); err == nil {
+		t.Error("expected error updating a template with Html and Json")
+	}
+	if requests != 0 {
+		t.Errorf("expected conflicting requests not to reach the server, got %d", requests)
+	}
+}
+
 func TestUpdateTemplate(t *testing.T) {
 	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/templates/welcome" {
@@ -908,6 +996,31 @@ func TestDeleteTemplate(t *testing.T) {
 	}
 }
 
+func TestDeleteTemplateNoContent(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/templates/welcome":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Template not found."}`))
+		}
+	})
+	defer server.Close()
+
+	if _, err := client.Templates.Delete(context.Background(), "welcome", nil); err != nil {
+		t.Fatalf("expected 204 to be treated as success, got %v", err)
+	}
+	_, err := client.Templates.Delete(context.Background(), "missing", nil)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
 func TestGetMergeTags(t *testing.T) {
 	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/templates/welcome/merge-tags" {
@@ -1011,6 +1124,54 @@ func TestListProjects(t *testing.T) {
 	}
 }
 
+func TestCreateDomainWithTrackingDomain(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body CreateDomainRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.TrackingDomain != "track.example.com" {
+			t.Errorf("expected tracking domain to be forwarded, got %q", body.TrackingDomain)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"message":"Domain created.","data":{"domain":"example.com","status":"pending","status_label":"Pending","tracking_domain":"track.example.com"}}`))
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := client.Domains.Create(ctx, &CreateDomainRequest{Domain: "example.com", TrackingDomain: "track.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.TrackingDomain == nil || *resp.Data.TrackingDomain != "track.example.com" {
+		t.Errorf("unexpected tracking domain: %v", resp.Data.TrackingDomain)
+	}
+
+	for _, td := range []string{"track.other.com", "notexample.com", "bad_host.example.com"} {
+		if _, err := client.Domains.Create(ctx, &CreateDomainRequest{Domain: "example.com", TrackingDomain: td}); err == nil {
+			t.Errorf("%s: expected error", td)
+		}
+	}
+}
+
+func TestListProjectsTemplateCount(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"projects":[{"id":1,"name":"Default","template_count":12},{"id":2,"name":"Legacy"}],"pagination":{"total":2,"per_page":25,"current_page":1,"last_page":1}}}`))
+	})
+	defer server.Close()
+
+	resp, err := client.Projects.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Data.Projects[0].TemplateCount; got != 12 {
+		t.Errorf("expected 12 templates, got %d", got)
+	}
+	if got := resp.Data.Projects[1].TemplateCount; got != 0 {
+		t.Errorf("expected missing count to decode as 0, got %d", got)
+	}
+}
+
 func TestEmailEventRcptMetaPolymorphic(t *testing.T) {
 	// Per spec: rcpt_meta is object|null for list items and array|null
 	// for event-stream payloads. The SDK must decode both shapes.
@@ -1077,3 +1238,3711 @@ func TestWebhookNullEventTypes(t *testing.T) {
 		t.Errorf("expected %d event types, got %d", len(events), len(*wh2.EventTypes))
 	}
 }
+
+func TestSendEmailRequestIdempotencyKey(t *testing.T) {
+	a := &SendEmailRequest{From: "sender@example.com", To: []string{"a@example.com"}, Subject: "Hi"}
+	b := &SendEmailRequest{From: "sender@example.com", To: []string{"a@example.com"}, Subject: "Hi"}
+	c := &SendEmailRequest{From: "sender@example.com", To: []string{"b@example.com"}, Subject: "Hi"}
+
+	if a.IdempotencyKey() != b.IdempotencyKey() {
+		t.Errorf("expected identical requests to yield identical keys, got %q and %q", a.IdempotencyKey(), b.IdempotencyKey())
+	}
+	if a.IdempotencyKey() == c.IdempotencyKey() {
+		t.Errorf("expected different requests to yield different keys, both got %q", a.IdempotencyKey())
+	}
+
+	s1 := &ScheduleEmailRequest{SendEmailRequest: *a, ScheduledAt: "2024-12-25T10:00:00Z"}
+	s2 := &ScheduleEmailRequest{SendEmailRequest: *a, ScheduledAt: "2024-12-26T10:00:00Z"}
+	if s1.IdempotencyKey() == s2.IdempotencyKey() {
+		t.Error("expected different scheduled times to yield different keys")
+	}
+}
+
+func TestSendEmailAutoIdempotency(t *testing.T) {
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Html:    "<h1>Hello!</h1>",
+	}
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{Data: SendEmailData{RequestID: "req-123"}})
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions("test-api-key", WithAutoIdempotency())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.SetBaseURL(server.URL + "/"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Emails.Send(context.Background(), params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(got) != 2 || got[0] != params.IdempotencyKey() || got[1] != got[0] {
+		t.Errorf("expected both sends to carry key %q, got %v", params.IdempotencyKey(), got)
+	}
+
+	// Sends differing only in priority must not be deduplicated.
+	urgent := *params
+	urgent.Options = &SendEmailOptions{Priority: PriorityHigh}
+	if _, err := client.Emails.Send(context.Background(), &urgent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[2] == got[0] {
+		t.Errorf("expected a different key for a different priority, got %v", got)
+	}
+
+	// Without the option no header is sent.
+	plain, server2 := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			t.Errorf("expected no Idempotency-Key header, got %q", key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server2.Close()
+
+	if _, err := plain.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestListTemplatesByFolder(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "folder_id=7&project_id=5" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListTemplatesResponse{
+			Message: "Templates retrieved successfully.",
+			Data: ListTemplatesData{
+				Templates: []Template{
+					{ID: 1, Slug: "welcome", ProjectID: 5, FolderID: 7},
+					{ID: 2, Slug: "receipt", ProjectID: 5, FolderID: 7},
+				},
+				Pagination: PagePagination{Total: 2, PerPage: 25, CurrentPage: 1, LastPage: 1},
+			},
+		})
+	})
+	defer server.Close()
+
+	resp, err := client.Templates.List(context.Background(), &ListTemplatesParams{
+		ProjectID: 5,
+		FolderID:  7,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Data.Templates) != 2 {
+		t.Fatalf("expected 2 templates, got %d", len(resp.Data.Templates))
+	}
+	for _, tmpl := range resp.Data.Templates {
+		if tmpl.FolderID != 7 {
+			t.Errorf("expected folder 7, got %d for %q", tmpl.FolderID, tmpl.Slug)
+		}
+	}
+}
+
+func TestMoveTemplate(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates/42" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPatch {
+			t.Errorf("expected PATCH, got %s", r.Method)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		if string(raw) != `{"folder_id":7}` {
+			t.Errorf("unexpected body: %s", raw)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetTemplateResponse{
+			Message: "Template updated.",
+			Data:    TemplateDetail{ID: 42, Slug: "welcome", FolderID: 7},
+		})
+	})
+	defer server.Close()
+
+	resp, err := client.Templates.Move(context.Background(), 42, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.FolderID != 7 {
+		t.Errorf("expected folder 7, got %d", resp.Data.FolderID)
+	}
+
+	if _, err := client.Templates.Move(context.Background(), 42, 0); err == nil {
+		t.Error("expected error for invalid folder ID")
+	}
+}
+
+func TestSendTemplateTest(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates/42/test" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		to, _ := body["to"].([]interface{})
+		if len(to) != 1 || to[0] != "designer@example.com" {
+			t.Errorf("unexpected to: %v", body["to"])
+		}
+		data, _ := body["substitution_data"].(map[string]interface{})
+		if data["FIRST_NAME"] != "Ada" {
+			t.Errorf("unexpected substitution_data: %v", body["substitution_data"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{
+			Message: "Test email queued.",
+			Data:    SendEmailData{RequestID: "req-test", Accepted: 1},
+		})
+	})
+	defer server.Close()
+
+	resp, err := client.Templates.SendTest(context.Background(), 42, []string{"designer@example.com"},
+		map[string]interface{}{"FIRST_NAME": "Ada"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.RequestID != "req-test" {
+		t.Errorf("expected request ID %q, got %q", "req-test", resp.Data.RequestID)
+	}
+}
+
+func TestDefaultProject(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/default" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Project retrieved.","data":{"id":3,"name":"Default","team_id":10,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","emoji":null}}`))
+	})
+	defer server.Close()
+
+	project, err := client.Projects.Default(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project.ID != 3 {
+		t.Errorf("expected project ID 3, got %d", project.ID)
+	}
+	if project.Name != "Default" {
+		t.Errorf("expected name %q, got %q", "Default", project.Name)
+	}
+}
+
+func TestWithHeaderRequestOption(t *testing.T) {
+	var flags []string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		flags = append(flags, r.Header.Get("X-Feature-Flag"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListDomainsResponse{})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := client.Domains.List(ctx, WithHeader("X-Feature-Flag", "beta")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Domains.List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(flags) != 2 || flags[0] != "beta" || flags[1] != "" {
+		t.Errorf("expected header on first call only, got %q", flags)
+	}
+
+	_, err := client.Domains.List(ctx, WithHeader("authorization", "Bearer other"))
+	if err == nil {
+		t.Fatal("expected error for restricted header")
+	}
+	if len(flags) != 2 {
+		t.Errorf("expected rejected call not to reach the server, got %d requests", len(flags))
+	}
+}
+
+func TestClientConfigRedactsAPIKey(t *testing.T) {
+	client, err := NewClientWithOptions("sk_live_abcdef123456", WithAutoIdempotency())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := client.Config()
+	if cfg.APIKey != "****************3456" {
+		t.Errorf("expected masked key, got %q", cfg.APIKey)
+	}
+	if cfg.BaseURL != defaultBaseURL {
+		t.Errorf("expected base URL %q, got %q", defaultBaseURL, cfg.BaseURL)
+	}
+	if cfg.UserAgent != userAgent {
+		t.Errorf("expected user agent %q, got %q", userAgent, cfg.UserAgent)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("expected timeout 30s, got %s", cfg.Timeout)
+	}
+	if !cfg.AutoIdempotency {
+		t.Error("expected AutoIdempotency to be reported")
+	}
+
+	if short := NewClient("abc").Config().APIKey; short != "***" {
+		t.Errorf("expected short key to be fully masked, got %q", short)
+	}
+}
+
+func TestValidateEmailAddress(t *testing.T) {
+	valid := []string{
+		"jane@example.com",
+		"Jane Doe <jane@example.com>",
+		`"Doe, Jane" <jane@example.com>`,
+	}
+	for _, addr := range valid {
+		if err := ValidateEmailAddress(addr); err != nil {
+			t.Errorf("expected %q to be valid, got %v", addr, err)
+		}
+	}
+
+	invalid := []string{"", "jane", "jane@", "Jane <jane@example.com", "@example.com"}
+	for _, addr := range invalid {
+		if err := ValidateEmailAddress(addr); err == nil {
+			t.Errorf("expected %q to be invalid", addr)
+		}
+	}
+}
+
+func TestSendEmailValidatesAddresses(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected invalid request not to reach the server")
+	})
+	defer server.Close()
+
+	cases := []*SendEmailRequest{
+		{From: "not-an-address", To: []string{"recipient@example.com"}},
+		{From: "sender@example.com", To: []string{"recipient@example.com", "bad"}},
+		{From: "sender@example.com", To: []string{"recipient@example.com"}, Cc: []string{"bad"}},
+		{From: "sender@example.com", To: []string{"recipient@example.com"}, Bcc: []string{"bad@"}},
+		{From: "sender@example.com", To: []string{"recipient@example.com"}, ReplyTo: "bad"},
+	}
+	for i, params := range cases {
+		if _, err := client.Emails.Send(context.Background(), params); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+	_, err := client.Emails.Send(context.Background(), cases[2])
+	if want := ValidateEmailAddress("bad"); err == nil || !strings.Contains(err.Error(), "invalid cc address: "+want.Error()) {
+		t.Errorf("expected the cc field to wrap ValidateEmailAddress's error, got %v", err)
+	}
+
+	_, err = client.Emails.Schedule(context.Background(), &ScheduleEmailRequest{
+		SendEmailRequest: SendEmailRequest{From: "sender@example.com", To: []string{"bad"}},
+		ScheduledAt:      "2024-12-25T10:00:00Z",
+	})
+	if err == nil {
+		t.Error("expected validation error from Schedule")
+	}
+}
+
+func TestSendEmailPriorityHeaders(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		if bytes.Contains(raw, []byte(`"priority"`)) {
+			t.Errorf("did not expect priority option in body, got: %s", raw)
+		}
+
+		var body SendEmailRequest
+		json.Unmarshal(raw, &body)
+		if got := body.Headers["X-Priority"]; got != "1 (Highest)" {
+			t.Errorf("expected X-Priority %q, got %q", "1 (Highest)", got)
+		}
+		if got := body.Headers["Importance"]; got != "high" {
+			t.Errorf("expected Importance %q, got %q", "high", got)
+		}
+		if got := body.Headers["X-Custom"]; got != "kept" {
+			t.Errorf("expected custom header to be kept, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server.Close()
+
+	params := &SendEmailRequest{
+		From:    "alerts@example.com",
+		To:      []string{"oncall@example.com"},
+		Subject: "Disk full",
+		Text:    "Disk full",
+		Headers: map[string]string{"X-Custom": "kept"},
+		Options: &SendEmailOptions{Priority: PriorityHigh},
+	}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(params.Headers) != 1 {
+		t.Errorf("expected caller's headers to be left untouched, got %v", params.Headers)
+	}
+
+	params.Options.Priority = "urgent"
+	if _, err := client.Emails.Send(context.Background(), params); err == nil {
+		t.Error("expected error for invalid priority")
+	}
+}
+
+func TestDomainBounceRate(t *testing.T) {
+	sent := 200
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domains/example.com/stats" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if from := r.URL.Query().Get("from"); from != "2024-01-01T00:00:00Z" {
+			t.Errorf("unexpected from: %q", from)
+		}
+		if to := r.URL.Query().Get("to"); to != "2024-01-31T00:00:00Z" {
+			t.Errorf("unexpected to: %q", to)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DomainStatsResponse{
+			Data: DomainStats{Domain: "example.com", Sent: sent, Delivered: sent - sent/20, Bounced: sent / 20},
+		})
+	})
+	defer server.Close()
+
+	from := time.Date(2024, 1, 1, 2, 0, 0, 0, time.FixedZone("EET", 2*60*60))
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	rate, err := client.Domains.BounceRate(context.Background(), "example.com", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate != 0.05 {
+		t.Errorf("expected bounce rate 0.05, got %v", rate)
+	}
+
+	sent = 0
+	if _, err := client.Domains.BounceRate(context.Background(), "example.com", from, to); !errors.Is(err, ErrNoSends) {
+		t.Errorf("expected ErrNoSends, got %v", err)
+	}
+
+	if _, err := client.Domains.BounceRate(context.Background(), "example.com", to, from); err == nil {
+		t.Error("expected error for inverted range")
+	}
+}
+
+func TestListTemplatesCreatedRange(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("created_after"); got != "2024-01-01T00:00:00Z" {
+			t.Errorf("unexpected created_after: %q", got)
+		}
+		if got := q.Get("created_before"); got != "2024-02-01T00:00:00Z" {
+			t.Errorf("unexpected created_before: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListTemplatesResponse{})
+	})
+	defer server.Close()
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 2, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	_, err := client.Templates.List(context.Background(), &ListTemplatesParams{
+		CreatedAfter:  after,
+		CreatedBefore: before,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.Templates.List(context.Background(), &ListTemplatesParams{
+		CreatedAfter:  before,
+		CreatedBefore: after,
+	})
+	if err == nil {
+		t.Error("expected error for inverted range")
+	}
+}
+
+func TestCursorPaginationTotal(t *testing.T) {
+	var withTotal, withoutTotal CursorPagination
+	if err := json.Unmarshal([]byte(`{"next_cursor":"abc","per_page":10,"total":1234}`), &withTotal); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if withTotal.Total == nil || *withTotal.Total != 1234 {
+		t.Errorf("expected total 1234, got %v", withTotal.Total)
+	}
+	if err := json.Unmarshal([]byte(`{"next_cursor":"abc","per_page":10}`), &withoutTotal); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if withoutTotal.Total != nil {
+		t.Errorf("expected nil total when absent, got %d", *withoutTotal.Total)
+	}
+}
+
+func TestListEmailsNextParams(t *testing.T) {
+	var page ListEmailsResponse
+	if err := json.Unmarshal([]byte(`{"message":"ok","data":{"events":{"data":[{"event_id":"evt-1"}],"total_count":1}}}`), &page); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if next := page.NextParams(&ListEmailsParams{PerPage: 10}); next != nil {
+		t.Errorf("expected missing pagination to be terminal, got %+v", next)
+	}
+
+	page.Data.Events.Pagination = CursorPagination{NextCursor: strPtr("")}
+	if next := page.NextParams(nil); next != nil {
+		t.Errorf("expected empty cursor to be terminal, got %+v", next)
+	}
+
+	page.Data.Events.Pagination = CursorPagination{NextCursor: strPtr("abc")}
+	if next := page.NextParams(&ListEmailsParams{Cursor: "abc"}); next != nil {
+		t.Errorf("expected repeated cursor to be terminal, got %+v", next)
+	}
+
+	params := &ListEmailsParams{PerPage: 10, Recipients: "user@example.com"}
+	next := page.NextParams(params)
+	if next == nil {
+		t.Fatal("expected next params")
+	}
+	if next.Cursor != "abc" || next.PerPage != 10 || next.Recipients != "user@example.com" {
+		t.Errorf("unexpected next params: %+v", next)
+	}
+	if params.Cursor != "" {
+		t.Error("expected original params to be left untouched")
+	}
+
+	var events ListEmailEventsResponse
+	if err := json.Unmarshal([]byte(`{"message":"ok","data":{"events":{"data":[]}}}`), &events); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if next := events.NextParams(nil); next != nil {
+		t.Errorf("expected missing pagination to be terminal, got %+v", next)
+	}
+}
+
+func TestListAllEmails(t *testing.T) {
+	var queries []string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"message":"ok","data":{"events":{"data":[{"event_id":"evt-1"},{"event_id":"evt-2"}],"pagination":{"next_cursor":"page2","per_page":2}}}}`))
+			return
+		}
+		w.Write([]byte(`{"message":"ok","data":{"events":{"data":[{"event_id":"evt-3"}],"pagination":{"next_cursor":null,"per_page":2}}}}`))
+	})
+	defer server.Close()
+
+	it := client.Emails.ListAll(context.Background(), &ListEmailsParams{PerPage: 2})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Event().EventID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"evt-1", "evt-2", "evt-3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected events %v, got %v", want, ids)
+	}
+	if want := []string{"per_page=2", "cursor=page2&per_page=2"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("expected queries %v, got %v", want, queries)
+	}
+	if it.Next() {
+		t.Error("expected exhausted iterator to stay exhausted")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it = client.Emails.ListAll(ctx, nil)
+	if !it.Next() {
+		t.Fatalf("expected first event, got error %v", it.Err())
+	}
+	cancel()
+	for it.Next() {
+	}
+	if it.Err() != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", it.Err())
+	}
+
+	failing, failingServer := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"Server error"}`))
+	})
+	defer failingServer.Close()
+	it = failing.Emails.ListAll(context.Background(), nil)
+	if it.Next() {
+		t.Error("expected no events from failing server")
+	}
+	if e, ok := it.Err().(*Error); !ok || e.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected API error, got %v", it.Err())
+	}
+}
+
+func TestListAllTemplates(t *testing.T) {
+	var queries []string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"message":"ok","data":{"templates":[{"id":%d},{"id":%d}],"pagination":{"total":6,"per_page":2,"current_page":%d,"last_page":3}}}`,
+			page*10+1, page*10+2, page)
+	})
+	defer server.Close()
+
+	it := client.Templates.ListAll(context.Background(), &ListTemplatesParams{ProjectID: 7, PerPage: 2})
+	var ids []int
+	for it.Next() {
+		ids = append(ids, it.Template().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{11, 12, 21, 22, 31, 32}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected templates %v, got %v", want, ids)
+	}
+	want := []string{
+		"per_page=2&project_id=7",
+		"page=2&per_page=2&project_id=7",
+		"page=3&per_page=2&project_id=7",
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("expected queries %v, got %v", want, queries)
+	}
+
+	for name, body := range map[string]string{
+		"empty":       `{"message":"ok","data":{"templates":[],"pagination":{"total":0,"per_page":25,"current_page":1,"last_page":1}}}`,
+		"last page 0": `{"message":"ok","data":{"templates":[{"id":1}],"pagination":{"total":1,"per_page":25,"current_page":1,"last_page":0}}}`,
+	} {
+		requests := 0
+		client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		})
+		it := client.Templates.ListAll(context.Background(), nil)
+		for it.Next() {
+		}
+		if it.Err() != nil || requests != 1 {
+			t.Errorf("%s: expected a single request and no error, got %d requests and %v", name, requests, it.Err())
+		}
+		server.Close()
+	}
+}
+
+func TestWaitUntilVerified(t *testing.T) {
+	polls := 0
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domains/example.com" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetDomainResponse{
+			Data: DomainDetail{Domain: "example.com", Status: "pending", CanSend: polls >= 3},
+		})
+	})
+	defer server.Close()
+	clock := &fakeClock{}
+	client.clock = clock
+
+	detail, err := client.Domains.WaitUntilVerified(context.Background(), "example.com", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !detail.CanSend || polls != 3 {
+		t.Errorf("expected verified detail on the third poll, got %+v after %d polls", detail, polls)
+	}
+	if want := []time.Duration{time.Minute, time.Minute}; !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("expected sleeps %v, got %v", want, clock.sleeps)
+	}
+
+	polls = -10
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	detail, err = client.Domains.WaitUntilVerified(ctx, "example.com", time.Minute)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if detail != nil {
+		t.Errorf("expected no detail when the first poll is cancelled, got %+v", detail)
+	}
+
+	polls = 0
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := client.Domains.WaitUntilVerified(context.Background(), "example.com", interval); err == nil {
+			t.Errorf("expected error for interval %v", interval)
+		}
+	}
+	if polls != 0 {
+		t.Errorf("expected no polls for invalid intervals, got %d", polls)
+	}
+}
+
+func TestWaitUntilAllVerified(t *testing.T) {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		domain := strings.TrimPrefix(r.URL.Path, "/domains/")
+		mu.Lock()
+		polls[domain]++
+		n := polls[domain]
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetDomainResponse{
+			Data: DomainDetail{Domain: domain, CanSend: domain != "slow.com" && n >= 2},
+		})
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	domains := []string{"a.com", "b.com", "slow.com"}
+	details, err := client.Domains.WaitUntilAllVerified(ctx, domains, 5*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected error for the domain that never verified")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	waitErrs, ok := err.(DomainWaitErrors)
+	if !ok {
+		t.Fatalf("expected DomainWaitErrors, got %T", err)
+	}
+	if len(waitErrs) != 1 || waitErrs["slow.com"] == nil {
+		t.Errorf("expected only slow.com to fail, got %v", waitErrs)
+	}
+
+	for _, domain := range []string{"a.com", "b.com"} {
+		if d := details[domain]; d == nil || !d.CanSend {
+			t.Errorf("expected %s to be verified, got %+v", domain, d)
+		}
+	}
+	if d := details["slow.com"]; d == nil || d.CanSend {
+		t.Errorf("expected last unverified detail for slow.com, got %+v", d)
+	}
+
+	if _, err := client.Domains.WaitUntilAllVerified(context.Background(), domains, 0); err == nil {
+		t.Error("expected error for zero interval")
+	} else if _, ok := err.(DomainWaitErrors); ok {
+		t.Errorf("expected a validation error rather than per-domain errors, got %v", err)
+	}
+}
+
+func TestListEmailsSearch(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "from=2024-01-01&q=order+%2342&recipients=user%40example.com" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		if q := r.URL.Query().Get("q"); q != "order #42" {
+			t.Errorf("expected q=%q, got %q", "order #42", q)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListEmailsResponse{})
+	})
+	defer server.Close()
+
+	_, err := client.Emails.List(context.Background(), &ListEmailsParams{
+		Search:     "order #42",
+		Recipients: "user@example.com",
+		From:       "2024-01-01",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetDomainDNS(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domains/example.com/dns" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"DNS records retrieved.","data":{"dkim":{"selector":"scph0124","public":"MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC","headers":"from:to:subject:date"}}}`))
+	})
+	defer server.Close()
+
+	dns, err := client.Domains.DNS(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dns.DKIM == nil {
+		t.Fatal("expected DKIM record")
+	}
+	if dns.DKIM.Selector != "scph0124" {
+		t.Errorf("expected selector %q, got %q", "scph0124", dns.DKIM.Selector)
+	}
+	if dns.DKIM.Public != "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC" {
+		t.Errorf("unexpected public key: %q", dns.DKIM.Public)
+	}
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	ms := time.Millisecond
+
+	constant := ConstantBackoff{Delay: 100 * ms}
+	for attempt := 1; attempt <= 3; attempt++ {
+		if d := constant.NextDelay(attempt); d != 100*ms {
+			t.Errorf("constant attempt %d: expected 100ms, got %s", attempt, d)
+		}
+	}
+
+	linear := LinearBackoff{Base: 100 * ms, Max: 250 * ms}
+	for i, want := range []time.Duration{100 * ms, 200 * ms, 250 * ms, 250 * ms} {
+		if d := linear.NextDelay(i + 1); d != want {
+			t.Errorf("linear attempt %d: expected %s, got %s", i+1, want, d)
+		}
+	}
+
+	exponential := ExponentialBackoff{Base: 100 * ms, Max: time.Second}
+	for i, want := range []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, time.Second, time.Second} {
+		if d := exponential.NextDelay(i + 1); d != want {
+			t.Errorf("exponential attempt %d: expected %s, got %s", i+1, want, d)
+		}
+	}
+	if d := exponential.NextDelay(200); d != time.Second {
+		t.Errorf("exponential: expected large attempts to stay capped, got %s", d)
+	}
+
+	jitter := NewDecorrelatedJitterBackoff(100*ms, time.Second)
+	for run := 0; run < 2; run++ {
+		prev := 100 * ms
+		for attempt := 1; attempt <= 10; attempt++ {
+			d := jitter.NextDelay(attempt)
+			upper := prev * 3
+			if upper > time.Second {
+				upper = time.Second
+			}
+			if d < 100*ms || d > upper {
+				t.Errorf("jitter attempt %d: %s outside [100ms, %s]", attempt, d, upper)
+			}
+			prev = d
+		}
+	}
+}
+
+func TestTrackingDefaults(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account/tracking" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Tracking defaults retrieved.","data":{"open_tracking":true,"click_tracking":false}}`))
+	})
+	defer server.Close()
+
+	defaults, err := client.TrackingDefaults(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !defaults.OpenTracking {
+		t.Error("expected open tracking to be enabled")
+	}
+	if defaults.ClickTracking {
+		t.Error("expected click tracking to be disabled")
+	}
+}
+
+func TestFormatFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "jane@example.com"},
+		{"Jane Doe", `"Jane Doe" <jane@example.com>`},
+		{"Doe, Jane", `"Doe, Jane" <jane@example.com>`},
+		{`Jane "JD" Doe`, `"Jane \"JD\" Doe" <jane@example.com>`},
+		{"Zoë Ünal", "=?utf-8?q?Zo=C3=AB_=C3=9Cnal?= <jane@example.com>"},
+	}
+	for _, tt := range tests {
+		got := FormatFrom("jane@example.com", tt.name)
+		if got != tt.want {
+			t.Errorf("FormatFrom(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		parsed, err := mail.ParseAddress(got)
+		if err != nil || parsed.Name != tt.name || parsed.Address != "jane@example.com" {
+			t.Errorf("FormatFrom(%q) = %q does not round-trip: %+v, %v", tt.name, got, parsed, err)
+		}
+	}
+}
+
+func TestSampleRecipients(t *testing.T) {
+	recipients := make([]string, 100)
+	for i := range recipients {
+		recipients[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+
+	a, err := SampleRecipients(recipients, 0.05, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := SampleRecipients(recipients, 0.05, 42)
+	if len(a) != 5 {
+		t.Fatalf("expected 5 recipients, got %d", len(a))
+	}
+	if strings.Join(a, ",") != strings.Join(b, ",") {
+		t.Errorf("expected the same seed to yield the same sample, got %v and %v", a, b)
+	}
+	if c, _ := SampleRecipients(recipients, 0.05, 43); strings.Join(a, ",") == strings.Join(c, ",") {
+		t.Errorf("expected a different seed to yield a different sample, got %v", c)
+	}
+
+	if got, err := SampleRecipients(recipients, 0, 1); err != nil || len(got) != 0 {
+		t.Errorf("expected empty sample for fraction 0, got %v (%v)", got, err)
+	}
+	if got, err := SampleRecipients(recipients, 1, 1); err != nil || strings.Join(got, ",") != strings.Join(recipients, ",") {
+		t.Errorf("expected fraction 1 to return every recipient in order, got error %v", err)
+	}
+
+	for _, fraction := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := SampleRecipients(recipients, fraction, 1); err == nil {
+			t.Errorf("expected error for fraction %v", fraction)
+		}
+	}
+}
+
+func TestSendEmailNormalizeRecipients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body SendEmailRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Join(body.To, ",") != "jane@example.com,john@example.com" {
+			t.Errorf("unexpected to: %v", body.To)
+		}
+		if strings.Join(body.Cc, ",") != "cc@example.com" {
+			t.Errorf("unexpected cc: %v", body.Cc)
+		}
+		if strings.Join(body.Bcc, ",") != "bcc@example.com" {
+			t.Errorf("unexpected bcc: %v", body.Bcc)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions("test-api-key", WithNormalizeRecipients())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"Jane Doe <jane@example.com>", "john@example.com"},
+		Cc:      []string{`"Doe, Carl" <cc@example.com>`},
+		Bcc:     []string{"bcc@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+	}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.To[0] != "Jane Doe <jane@example.com>" {
+		t.Errorf("expected caller's recipients to be left untouched, got %v", params.To)
+	}
+}
+
+func TestWebhookSigningKey(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	var calls int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodGet || r.URL.Path != "/webhooks/signing-key" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SigningKeyResponse{
+			Message: "Signing key retrieved successfully.",
+			Data:    SigningKeyData{SigningKey: pemKey},
+		})
+	})
+	defer server.Close()
+
+	got, err := client.Webhooks.SigningKey(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Webhooks.SigningKey(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the key to be fetched once, got %d requests", calls)
+	}
+
+	block, _ := pem.Decode([]byte(got))
+	if block == nil {
+		t.Fatal("expected a PEM-encoded key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+
+	payload := []byte(`{"type":"message.delivery"}`)
+	digest := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), digest[:], sig) {
+		t.Error("expected signature to verify against the fetched key")
+	}
+}
+
+func TestUploadAttachmentInChunks(t *testing.T) {
+	defer func(size int) { attachmentChunkSize = size }(attachmentChunkSize)
+	attachmentChunkSize = 8
+
+	content := "0123456789abc"
+	var chunks []uploadChunkRequest
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/emails/attachments/uploads":
+			var body createUploadRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Name != "notes.txt" || body.Type != "text/plain" {
+				t.Errorf("unexpected upload request: %+v", body)
+			}
+			w.Write([]byte(`{"message":"Upload started.","data":{"upload_id":"up_1"}}`))
+		case "/emails/attachments/uploads/up_1/chunks":
+			var body uploadChunkRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			chunks = append(chunks, body)
+			w.Write([]byte(`{"message":"Chunk received."}`))
+		case "/emails/attachments/uploads/up_1/complete":
+			w.Write([]byte(`{"message":"Upload complete.","data":{"attachment_ref":"att_123"}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	ref, err := client.Emails.UploadAttachment(context.Background(), strings.NewReader(content), "notes.txt", "text/plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref != "att_123" {
+		t.Errorf("expected ref %q, got %q", "att_123", ref)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Offset != 0 || chunks[1].Offset != 8 {
+		t.Errorf("unexpected offsets: %d, %d", chunks[0].Offset, chunks[1].Offset)
+	}
+	var got []byte
+	for _, c := range chunks {
+		b, err := base64.StdEncoding.DecodeString(c.Data)
+		if err != nil {
+			t.Fatalf("chunk is not base64: %v", err)
+		}
+		got = append(got, b...)
+	}
+	if string(got) != content {
+		t.Errorf("expected content %q, got %q", content, got)
+	}
+}
+
+func TestCampaignRun(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]string{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body SendEmailRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.TemplateSlug != "welcome" || body.From != "news@example.com" {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if body.To[0] == "bounce@example.com" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Recipient rejected."}`))
+			return
+		}
+		mu.Lock()
+		seen[body.To[0]] = body.SubstitutionData["name"]
+		mu.Unlock()
+		json.NewEncoder(w).Encode(SendEmailResponse{
+			Data: SendEmailData{RequestID: "req-" + body.To[0], Accepted: 1},
+		})
+	})
+	defer server.Close()
+
+	campaign := &Campaign{
+		TemplateSlug: "welcome",
+		From:         "news@example.com",
+		Recipients: []BatchRecipient{
+			{Email: "ann@example.com", SubstitutionData: map[string]string{"name": "Ann"}},
+			{Email: "bounce@example.com"},
+			{Email: "bob@example.com", SubstitutionData: map[string]string{"name": "Bob"}},
+		},
+		Concurrency: 2,
+	}
+	result, err := campaign.Run(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 2 || result.Failed != 1 {
+		t.Errorf("expected 2 sent and 1 failed, got %d and %d", result.Sent, result.Failed)
+	}
+	if seen["ann@example.com"] != "Ann" || seen["bob@example.com"] != "Bob" {
+		t.Errorf("unexpected substitution data: %v", seen)
+	}
+	if got := result.Recipients[0].RequestID; got != "req-ann@example.com" {
+		t.Errorf("expected results in recipient order, got %q first", got)
+	}
+	if !IsValidationError(result.Recipients[1].Err) {
+		t.Errorf("expected validation error for bounce recipient, got %v", result.Recipients[1].Err)
+	}
+
+	if _, err := (&Campaign{From: "news@example.com"}).Run(context.Background(), client); err == nil {
+		t.Error("expected error for campaign without template")
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body SendEmailRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.TemplateSlug != "newsletter" || len(body.To) != 1 || body.SubstitutionData["issue"] != "42" {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if body.To[0] == "bounce@example.com" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Recipient rejected."}`))
+			return
+		}
+		accepted := 1
+		if body.To[0] == "blocked@example.com" {
+			accepted = 0
+		}
+		json.NewEncoder(w).Encode(SendEmailResponse{
+			Data: SendEmailData{RequestID: body.To[0] + ":" + body.SubstitutionData["name"] + ":" + body.Metadata["segment"], Accepted: accepted},
+		})
+	})
+	defer server.Close()
+
+	result, err := client.Emails.SendBatch(context.Background(), &SendBatchRequest{
+		Email: SendEmailRequest{
+			From:             "news@example.com",
+			TemplateSlug:     "newsletter",
+			SubstitutionData: map[string]string{"issue": "42", "name": "friend"},
+		},
+		Recipients: []BatchRecipient{
+			{Email: "ann@example.com", SubstitutionData: map[string]string{"name": "Ann"}, Metadata: map[string]string{"segment": "a"}},
+			{Email: "bounce@example.com", SubstitutionData: map[string]string{"name": "Bo"}},
+			{Email: "cy@example.com", Metadata: map[string]string{"segment": "c"}},
+			{Email: "blocked@example.com"},
+		},
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 2 || result.Failed != 2 {
+		t.Errorf("expected 2 sent and 2 failed, got %d and %d", result.Sent, result.Failed)
+	}
+	if got := result.Recipients[0].RequestID; got != "ann@example.com:Ann:a" {
+		t.Errorf("unexpected first result %q", got)
+	}
+	if result.Recipients[1].Email != "bounce@example.com" || !IsValidationError(result.Recipients[1].Err) {
+		t.Errorf("expected validation error for second recipient, got %+v", result.Recipients[1])
+	}
+	if got := result.Recipients[2].RequestID; got != "cy@example.com:friend:c" {
+		t.Errorf("unexpected third result %q", got)
+	}
+	if err := result.Recipients[3].Err; err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("expected rejection error for fourth recipient, got %v", err)
+	}
+
+	for _, shared := range []SendEmailRequest{
+		{From: "news@example.com", To: []string{"x@example.com"}},
+		{From: "news@example.com", Cc: []string{"x@example.com"}},
+		{From: "news@example.com", Bcc: []string{"x@example.com"}},
+	} {
+		if _, err := client.Emails.SendBatch(context.Background(), &SendBatchRequest{
+			Email:      shared,
+			Recipients: []BatchRecipient{{Email: "ann@example.com"}},
+		}); err == nil {
+			t.Errorf("expected error when the shared email sets recipients: %+v", shared)
+		}
+	}
+}
+
+func TestSendBatchPartialError(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body SendEmailRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		if body.To[0] == "bounce@example.com" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Recipient rejected."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(SendEmailResponse{Data: SendEmailData{RequestID: "req-" + body.To[0], Accepted: 1}})
+	})
+	defer server.Close()
+
+	batch := &SendBatchRequest{
+		Email:          SendEmailRequest{From: "news@example.com", Subject: "Hi", Text: "Hello"},
+		Recipients:     []BatchRecipient{{Email: "ann@example.com"}, {Email: "bob@example.com"}},
+		ErrorOnFailure: true,
+	}
+	result, err := client.Emails.SendBatch(context.Background(), batch)
+	if err != nil || result.Sent != 2 {
+		t.Fatalf("expected no error when every recipient is accepted, got %v (%+v)", err, result)
+	}
+
+	batch.Recipients = append(batch.Recipients, BatchRecipient{Email: "bounce@example.com"})
+	result, err = client.Emails.SendBatch(context.Background(), batch)
+	var partial *BatchPartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *BatchPartialError, got %v", err)
+	}
+	if result == nil || partial.Result != result || result.Sent != 2 || result.Failed != 1 {
+		t.Errorf("expected the result alongside the error, got %+v", result)
+	}
+	if err.Error() != "lettr: 1 of 3 batch recipients failed" {
+		t.Errorf("unexpected message: %v", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected the recipient's *Error to be reachable, got %v", apiErr)
+	}
+}
+
+func TestSendBatchStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sends := 0
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sends++
+		cancel()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{Data: SendEmailData{RequestID: "req-1", Accepted: 1}})
+	})
+	defer server.Close()
+
+	result, err := client.Emails.SendBatch(ctx, &SendBatchRequest{
+		Email: SendEmailRequest{From: "news@example.com", Subject: "Hi", Text: "Hello"},
+		Recipients: []BatchRecipient{
+			{Email: "a@example.com"}, {Email: "b@example.com"}, {Email: "c@example.com"},
+		},
+		Concurrency: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sends != 1 {
+		t.Errorf("expected sending to stop after the context ended, got %d sends", sends)
+	}
+	for _, r := range result.Recipients[1:] {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("expected %s to fail with context.Canceled, got %v", r.Email, r.Err)
+		}
+	}
+}
+
+func TestForEachConcurrentlySkipsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var started, skipped int32
+	forEachConcurrently(ctx, 100, 100, func(int) {
+		atomic.AddInt32(&started, 1)
+	}, func(_ int, err error) {
+		if errors.Is(err, context.Canceled) {
+			atomic.AddInt32(&skipped, 1)
+		}
+	})
+	if started != 0 || skipped != 100 {
+		t.Errorf("expected every call to be skipped with context.Canceled, got %d started and %d skipped", started, skipped)
+	}
+}
+
+func TestPollBatch(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	polls := map[string]int{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/emails/")
+		polls[id]++
+		events := []EmailEvent{{EventID: "evt-0", Type: "message_event.injection"}}
+		switch {
+		case id == "req-b":
+			events = append(events, EmailEvent{EventID: "evt-1", Type: "message_event.bounce"})
+		case polls[id] == 1:
+			w.Header().Set(rateLimitRemainingHeader, "0")
+			w.Header().Set(rateLimitResetHeader, strconv.FormatInt(now.Add(2*time.Minute).Unix(), 10))
+		default:
+			events = append(events, EmailEvent{EventID: "evt-2", Type: "message_event.delivery"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetEmailResponse{Data: ScheduledTransmission{TransmissionID: id, Events: events}})
+	})
+	defer server.Close()
+	clock := &fakeClock{now: now}
+	client.clock = clock
+
+	if _, err := client.Emails.PollBatch(context.Background(), []string{"req-a"}, WaitOptions{}); err == nil {
+		t.Fatal("expected an error for a zero interval")
+	}
+
+	events, err := client.Emails.PollBatch(context.Background(), []string{"req-a", "req-b"}, WaitOptions{Interval: 30 * time.Second, Concurrency: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events["req-a"] == nil || events["req-a"].EventID != "evt-2" || events["req-b"] == nil || events["req-b"].EventID != "evt-1" {
+		t.Errorf("expected terminal events for both emails, got %+v", events)
+	}
+	if want := []time.Duration{2 * time.Minute}; !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("expected to wait for the rate limit reset, got sleeps %v", clock.sleeps)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Emails.PollBatch(ctx, []string{"req-c"}, WaitOptions{Interval: time.Second})
+	var pollErrs PollErrors
+	if !errors.As(err, &pollErrs) || pollErrs["req-c"] == nil || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected PollErrors wrapping context.Canceled, got %v", err)
+	}
+}
+
+func TestCampaignRunSchedulesPerRecipient(t *testing.T) {
+	var mu sync.Mutex
+	scheduled := map[string]string{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/emails/scheduled" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var body ScheduleEmailRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		scheduled[body.To[0]] = body.ScheduledAt
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ScheduleEmailResponse{
+			Data: ScheduleEmailData{RequestID: "sched-" + body.To[0], Accepted: 1},
+		})
+	})
+	defer server.Close()
+	now := time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)
+	client.clock = &fakeClock{now: now}
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	campaign := &Campaign{
+		TemplateSlug: "holiday",
+		From:         "news@example.com",
+		Recipients: []BatchRecipient{
+			{Email: "ann@example.com", SendAt: time.Date(2024, 12, 25, 9, 0, 0, 0, tokyo)},
+			{Email: "bob@example.com", SendAt: time.Date(2024, 12, 25, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+	result, err := campaign.Run(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 2 || result.Recipients[1].RequestID != "sched-bob@example.com" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := map[string]string{
+		"ann@example.com": "2024-12-25T00:00:00Z",
+		"bob@example.com": "2024-12-25T09:00:00Z",
+	}
+	if !reflect.DeepEqual(scheduled, want) {
+		t.Errorf("expected scheduled times %v, got %v", want, scheduled)
+	}
+
+	campaign.Recipients[1].SendAt = now.Add(-time.Minute)
+	if _, err := campaign.Run(context.Background(), client); err == nil {
+		t.Error("expected error for send time in the past")
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv(EnvAPIKey, "env-api-key")
+	t.Setenv(EnvBaseURL, "https://staging.example.com/api")
+	t.Setenv(EnvTimeout, "5s")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.apiKey != "env-api-key" {
+		t.Errorf("expected API key from env, got %q", client.apiKey)
+	}
+	if got := client.baseURL.String(); got != "https://staging.example.com/api/" {
+		t.Errorf("expected base URL from env, got %q", got)
+	}
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("expected 5s timeout, got %v", client.httpClient.Timeout)
+	}
+
+	t.Setenv(EnvTimeout, "soon")
+	if _, err := NewClientFromEnv(); err == nil || !strings.Contains(err.Error(), EnvTimeout) {
+		t.Errorf("expected error naming %s, got %v", EnvTimeout, err)
+	}
+
+	os.Unsetenv(EnvBaseURL)
+	os.Unsetenv(EnvTimeout)
+	client, err = NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := client.baseURL.String(); got != defaultBaseURL {
+		t.Errorf("expected default base URL, got %q", got)
+	}
+
+	os.Unsetenv(EnvAPIKey)
+	if _, err := NewClientFromEnv(); err == nil || !strings.Contains(err.Error(), EnvAPIKey) {
+		t.Errorf("expected error naming %s, got %v", EnvAPIKey, err)
+	}
+}
+
+func TestListPerPageValidation(t *testing.T) {
+	var requests int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{}}`))
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	for _, perPage := range []int{0, 1, 100} {
+		if _, err := client.Emails.List(ctx, &ListEmailsParams{PerPage: perPage}); err != nil {
+			t.Errorf("PerPage %d: unexpected error: %v", perPage, err)
+		}
+		if _, err := client.Templates.List(ctx, &ListTemplatesParams{PerPage: perPage}); err != nil {
+			t.Errorf("PerPage %d: unexpected error: %v", perPage, err)
+		}
+	}
+	if requests != 6 {
+		t.Fatalf("expected 6 requests, got %d", requests)
+	}
+
+	for _, perPage := range []int{-1, 101} {
+		if _, err := client.Emails.List(ctx, &ListEmailsParams{PerPage: perPage}); err == nil {
+			t.Errorf("PerPage %d: expected error for emails", perPage)
+		}
+		if _, err := client.Templates.List(ctx, &ListTemplatesParams{PerPage: perPage}); err == nil {
+			t.Errorf("PerPage %d: expected error for templates", perPage)
+		}
+		if _, err := client.Projects.List(ctx, &ListProjectsParams{PerPage: perPage}); err == nil {
+			t.Errorf("PerPage %d: expected error for projects", perPage)
+		}
+	}
+	if requests != 6 {
+		t.Errorf("expected out-of-range values to be rejected before sending, got %d requests", requests)
+	}
+}
+
+func TestListEmailsParamsMerge(t *testing.T) {
+	base := &ListEmailsParams{PerPage: 50, From: "2024-01-01", Recipients: "base@example.com"}
+	merged := base.Merge(&ListEmailsParams{Recipients: "user@example.com", Search: "invoice"})
+
+	want := ListEmailsParams{PerPage: 50, From: "2024-01-01", Recipients: "user@example.com", Search: "invoice"}
+	if !reflect.DeepEqual(*merged, want) {
+		t.Errorf("expected %+v, got %+v", want, *merged)
+	}
+	if base.Recipients != "base@example.com" || base.Search != "" {
+		t.Errorf("expected base to be left untouched, got %+v", *base)
+	}
+
+	var nilParams *ListEmailsParams
+	if got := nilParams.Merge(&ListEmailsParams{To: "2024-02-01"}); got.To != "2024-02-01" {
+		t.Errorf("expected merge onto nil to keep other's fields, got %+v", *got)
+	}
+	if got := base.Merge(nil); !reflect.DeepEqual(*got, *base) || got == base {
+		t.Errorf("expected a copy of base when other is nil, got %+v", *got)
+	}
+}
+
+func TestListExtraQueryParams(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q["tag"]; len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("expected extra tag params, got %v", got)
+		}
+		if got := q.Get("per_page"); got != "10" {
+			t.Errorf("expected known per_page to win, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{}}`))
+	})
+	defer server.Close()
+
+	extra := url.Values{"tag": {"a", "b"}, "per_page": {"99"}}
+	if _, err := client.Emails.List(context.Background(), &ListEmailsParams{PerPage: 10, Extra: extra}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Templates.List(context.Background(), &ListTemplatesParams{PerPage: 10, Extra: extra}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if extra.Get("per_page") != "99" {
+		t.Error("expected caller's extra values to be left untouched")
+	}
+
+	merged := (&ListEmailsParams{Extra: url.Values{"tag": {"a"}, "kind": {"x"}}}).Merge(&ListEmailsParams{Extra: url.Values{"tag": {"b"}}})
+	if merged.Extra.Get("tag") != "b" || merged.Extra.Get("kind") != "x" {
+		t.Errorf("unexpected merged extras: %v", merged.Extra)
+	}
+}
+
+func TestEmailTrackingInfo(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/emails/req-123/tracking" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Tracking info retrieved successfully.","data":{"open_tracking":true,"click_tracking":true,"open_tracking_pixel_url":"https://t.lettr.com/o/abc.gif","rewritten_link_count":3}}`))
+	})
+	defer server.Close()
+
+	info, err := client.Emails.TrackingInfo(context.Background(), "req-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.OpenTracking || !info.ClickTracking {
+		t.Errorf("expected open and click tracking, got %+v", info)
+	}
+	if info.OpenTrackingPixelURL == nil || *info.OpenTrackingPixelURL != "https://t.lettr.com/o/abc.gif" {
+		t.Errorf("unexpected pixel URL: %v", info.OpenTrackingPixelURL)
+	}
+	if info.RewrittenLinkCount != 3 {
+		t.Errorf("expected 3 rewritten links, got %d", info.RewrittenLinkCount)
+	}
+}
+
+func TestSeedTest(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/emails/seed-test" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["seed_list_id"] != "seed-list-1" || body["subject"] != "Launch" {
+			t.Errorf("unexpected body: %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Seed test completed.","data":{"seed_list_id":"seed-list-1","results":[
+			{"seed":"seed1@gmail.com","provider":"gmail","placement":"inbox"},
+			{"seed":"seed2@outlook.com","provider":"outlook","placement":"spam"},
+			{"seed":"seed3@yahoo.com","provider":"yahoo","placement":"missing"}
+		]}}`))
+	})
+	defer server.Close()
+
+	resp, err := client.Emails.SeedTest(context.Background(), &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Launch",
+		Html:    "<p>Hi</p>",
+	}, "seed-list-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []SeedPlacement{
+		{Seed: "seed1@gmail.com", Provider: "gmail", Placement: PlacementInbox},
+		{Seed: "seed2@outlook.com", Provider: "outlook", Placement: PlacementSpam},
+		{Seed: "seed3@yahoo.com", Provider: "yahoo", Placement: PlacementMissing},
+	}
+	if !reflect.DeepEqual(resp.Data.Results, want) {
+		t.Errorf("expected results %+v, got %+v", want, resp.Data.Results)
+	}
+
+	if _, err := client.Emails.SeedTest(context.Background(), &SendEmailRequest{}, ""); err == nil {
+		t.Error("expected error for empty seed list ID")
+	}
+}
+
+func TestSendWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := client.Emails.SendWithTimeout(50*time.Millisecond, &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the send to be cancelled promptly, took %v", elapsed)
+	}
+}
+
+func TestDomainSetupSteps(t *testing.T) {
+	done := func(steps []SetupStep) []bool {
+		out := make([]bool, len(steps))
+		for i, step := range steps {
+			out[i] = step.Done
+		}
+		return out
+	}
+
+	full := &DomainDetail{DkimStatus: strPtr("valid"), CnameStatus: strPtr("valid"), CanSend: true}
+	if got := done(full.SetupSteps()); !reflect.DeepEqual(got, []bool{true, true, true}) {
+		t.Errorf("expected all steps done, got %v", got)
+	}
+
+	partial := &DomainDetail{DkimStatus: strPtr("valid"), CnameStatus: strPtr("pending")}
+	steps := partial.SetupSteps()
+	if got := done(steps); !reflect.DeepEqual(got, []bool{true, false, false}) {
+		t.Errorf("expected only DKIM done, got %v", got)
+	}
+	if steps[1].ID != "cname" || steps[1].Label == "" {
+		t.Errorf("unexpected CNAME step: %+v", steps[1])
+	}
+
+	if got := done((&DomainDetail{}).SetupSteps()); !reflect.DeepEqual(got, []bool{false, false, false}) {
+		t.Errorf("expected no steps done for unknown statuses, got %v", got)
+	}
+}
+
+func TestSendEmailPresignedAttachment(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var raw map[string][]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		att := raw["attachments"][0]
+		if att["presigned_url"] != "https://bucket.s3.amazonaws.com/report.pdf?X-Amz-Signature=abc" {
+			t.Errorf("unexpected presigned_url: %v", att["presigned_url"])
+		}
+		if att["checksum"] != "sha256:deadbeef" {
+			t.Errorf("unexpected checksum: %v", att["checksum"])
+		}
+		if _, ok := att["data"]; ok {
+			t.Error("expected data to be omitted for presigned attachments")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server.Close()
+
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Report",
+		Text:    "Attached.",
+		Attachments: []Attachment{{
+			Name:         "report.pdf",
+			Type:         "application/pdf",
+			PresignedURL: "https://bucket.s3.amazonaws.com/report.pdf?X-Amz-Signature=abc",
+			Checksum:     "sha256:deadbeef",
+		}},
+	}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, att := range []Attachment{
+		{Name: "both.pdf", Data: "ZGF0YQ==", PresignedURL: "https://example.com/both.pdf"},
+		{Name: "neither.pdf"},
+		{Name: "relative.pdf", PresignedURL: "/files/relative.pdf"},
+	} {
+		params.Attachments = []Attachment{att}
+		if _, err := client.Emails.Send(context.Background(), params); err == nil || !strings.Contains(err.Error(), att.Name) {
+			t.Errorf("%s: expected validation error, got %v", att.Name, err)
+		}
+	}
+}
+
+func TestEmailEventTypedDetails(t *testing.T) {
+	clickJSON := `{"event_id":"e1","type":"click","target_link_url":"https://example.com/pricing","target_link_name":"Pricing","ip_address":"203.0.113.7","geo_ip":{"country":"US"},"new_field":"kept"}`
+	var click EmailEvent
+	if err := json.Unmarshal([]byte(clickJSON), &click); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if click.Click == nil || click.Bounce != nil || click.Open != nil {
+		t.Fatalf("expected only click details, got %+v", click)
+	}
+	if click.Click.TargetLinkURL != "https://example.com/pricing" || click.Click.TargetLinkName != "Pricing" {
+		t.Errorf("unexpected click details: %+v", click.Click)
+	}
+	if click.Click.GeoIp == nil || click.Click.GeoIp.Country != "US" {
+		t.Errorf("expected geo ip, got %+v", click.Click.GeoIp)
+	}
+	if click.TargetLinkURL == nil || *click.TargetLinkURL != "https://example.com/pricing" {
+		t.Error("expected flat fields to still be populated")
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(click.Raw, &raw); err != nil || raw["new_field"] != "kept" {
+		t.Errorf("expected unknown fields to be preserved in Raw, got %s", click.Raw)
+	}
+
+	bounceJSON := `{"event_id":"e2","type":"message.bounce","reason":"550 mailbox unavailable","raw_reason":"550 5.1.1 user unknown","error_code":"550","bounce_class":10}`
+	var bounce EmailEvent
+	if err := json.Unmarshal([]byte(bounceJSON), &bounce); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bounce.Bounce == nil || bounce.Click != nil {
+		t.Fatalf("expected only bounce details, got %+v", bounce)
+	}
+	if bounce.Bounce.BounceClass != 10 || bounce.Bounce.ErrorCode != "550" || bounce.Bounce.Reason != "550 mailbox unavailable" {
+		t.Errorf("unexpected bounce details: %+v", bounce.Bounce)
+	}
+
+	var delivery EmailEvent
+	if err := json.Unmarshal([]byte(`{"event_id":"e3","type":"delivery"}`), &delivery); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery.Click != nil || delivery.Bounce != nil || delivery.Open != nil {
+		t.Errorf("expected no typed details for delivery, got %+v", delivery)
+	}
+}
+
+func TestSetDomainSendingEnabled(t *testing.T) {
+	var bodies []string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/domains/example.com" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			CanSend bool `json:"can_send"`
+		}
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, strings.TrimSpace(string(b)))
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetDomainResponse{
+			Message: "Domain updated successfully.",
+			Data:    DomainDetail{Domain: "example.com", CanSend: body.CanSend},
+		})
+	})
+	defer server.Close()
+
+	resp, err := client.Domains.SetSendingEnabled(context.Background(), "example.com", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.CanSend {
+		t.Error("expected domain to be disabled")
+	}
+
+	resp, err = client.Domains.SetSendingEnabled(context.Background(), "example.com", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Data.CanSend {
+		t.Error("expected domain to be re-enabled")
+	}
+
+	want := []string{`{"can_send":false}`, `{"can_send":true}`}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("expected bodies %v, got %v", want, bodies)
+	}
+}
+
+func TestUpdateDomain(t *testing.T) {
+	var bodies []string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/domains/example.com" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body UpdateDomainRequest
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, strings.TrimSpace(string(b)))
+		json.Unmarshal(b, &body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetDomainResponse{
+			Message: "Domain updated successfully.",
+			Data:    DomainDetail{Domain: "example.com", TrackingDomain: body.TrackingDomain},
+		})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	tracking := "track.example.com"
+	resp, err := client.Domains.Update(ctx, "example.com", &UpdateDomainRequest{TrackingDomain: &tracking})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.TrackingDomain == nil || *resp.Data.TrackingDomain != tracking {
+		t.Errorf("expected tracking domain %q, got %v", tracking, resp.Data.TrackingDomain)
+	}
+
+	clear := ""
+	if _, err := client.Domains.Update(ctx, "example.com", &UpdateDomainRequest{TrackingDomain: &clear}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Domains.Update(ctx, "example.com", &UpdateDomainRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{`{"tracking_domain":"track.example.com"}`, `{"tracking_domain":""}`, `{}`}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("expected bodies %v, got %v", want, bodies)
+	}
+
+	invalid := "not a host"
+	if _, err := client.Domains.Update(ctx, "example.com", &UpdateDomainRequest{TrackingDomain: &invalid}); err == nil {
+		t.Error("expected error for invalid tracking domain")
+	}
+	other := "track.other.com"
+	if _, err := client.Domains.Update(ctx, "example.com", &UpdateDomainRequest{TrackingDomain: &other}); err == nil || !strings.Contains(err.Error(), "not a subdomain") {
+		t.Errorf("expected error for a tracking domain outside the domain, got %v", err)
+	}
+}
+
+func TestRotateDKIM(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/domains/example.com/dkim/rotate" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"DKIM key rotated.","data":{"selector":"lettr2024b","public":"MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC","headers":"from:to:subject:date","signing_domain":"example.com"}}`))
+	})
+	defer server.Close()
+
+	dkim, err := client.Domains.RotateDKIM(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &DomainDKIM{
+		Selector:      "lettr2024b",
+		Public:        "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC",
+		Headers:       "from:to:subject:date",
+		SigningDomain: "example.com",
+	}
+	if !reflect.DeepEqual(dkim, want) {
+		t.Errorf("expected %+v, got %+v", want, dkim)
+	}
+}
+
+func TestSendEmailTrackingDomain(t *testing.T) {
+	var bodies []map[string]interface{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server.Close()
+
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+		Options: &SendEmailOptions{TrackingDomain: "track.brand.example.com"},
+	}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	params.Options = &SendEmailOptions{}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	opts := bodies[0]["options"].(map[string]interface{})
+	if opts["tracking_domain"] != "track.brand.example.com" {
+		t.Errorf("expected tracking_domain to be sent, got %v", opts["tracking_domain"])
+	}
+	if opts, ok := bodies[1]["options"].(map[string]interface{}); ok {
+		if _, present := opts["tracking_domain"]; present {
+			t.Error("expected tracking_domain to be omitted when empty")
+		}
+	}
+
+	for _, domain := range []string{"localhost", "https://track.example.com", "-bad.example.com", "a..example.com"} {
+		params.Options = &SendEmailOptions{TrackingDomain: domain}
+		if _, err := client.Emails.Send(context.Background(), params); err == nil {
+			t.Errorf("%q: expected validation error", domain)
+		}
+	}
+	if len(bodies) != 2 {
+		t.Errorf("expected invalid tracking domains to be rejected before sending, got %d requests", len(bodies))
+	}
+}
+
+func TestTemplateStats(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates/42/stats" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("from"); got != "2024-03-01T00:00:00Z" {
+			t.Errorf("unexpected from: %q", got)
+		}
+		if got := r.URL.Query().Get("to"); got != "2024-03-31T00:00:00Z" {
+			t.Errorf("unexpected to: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TemplateStatsResponse{
+			Data: TemplateStats{TemplateID: 42, Sent: 200, Opened: 90, Clicked: 15},
+		})
+	})
+	defer server.Close()
+
+	stats, err := client.Templates.Stats(context.Background(), 42, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Data.Sent != 200 {
+		t.Errorf("expected 200 sent, got %d", stats.Data.Sent)
+	}
+	if got := stats.Data.OpenRate(); got != 0.45 {
+		t.Errorf("expected open rate 0.45, got %v", got)
+	}
+	if got := stats.Data.ClickRate(); got != 0.075 {
+		t.Errorf("expected click rate 0.075, got %v", got)
+	}
+	if got := (TemplateStats{}).OpenRate(); got != 0 {
+		t.Errorf("expected 0 open rate with no sends, got %v", got)
+	}
+
+	if _, err := client.Templates.Stats(context.Background(), 42, to, from); err == nil {
+		t.Error("expected error for inverted range")
+	}
+}
+
+func TestErrorToFieldMap(t *testing.T) {
+	apiErr := &Error{
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors: map[string][]string{
+			"from":    {"The sender email address is required.", "The sender must be a verified domain."},
+			"subject": {"The subject is required."},
+			"to":      {},
+		},
+	}
+
+	want := map[string]string{
+		"from":    "The sender email address is required.",
+		"subject": "The subject is required.",
+	}
+	if got := apiErr.ToFieldMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if len(apiErr.Errors["from"]) != 2 {
+		t.Error("expected the full messages to remain available")
+	}
+	if got := (&Error{}).ToFieldMap(); len(got) != 0 {
+		t.Errorf("expected empty map without field errors, got %v", got)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	scopes := `"scopes":["emails:send"],`
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/check" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"message":"ok","data":{%s"team_id":1,"timestamp":"2024-01-15T10:30:00Z"}}`, scopes)
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	if ok, err := client.HasScope(ctx, "emails:send"); err != nil || !ok {
+		t.Errorf("expected scoped key to have emails:send, got %v, %v", ok, err)
+	}
+	if ok, err := client.HasScope(ctx, "domains:write"); err != nil || ok {
+		t.Errorf("expected scoped key to lack domains:write, got %v, %v", ok, err)
+	}
+
+	scopes = ""
+	if ok, err := client.HasScope(ctx, "domains:write"); err != nil || !ok {
+		t.Errorf("expected unscoped key to have full access, got %v, %v", ok, err)
+	}
+}
+
+func TestSendEmailReplyToOmittedWhenEmpty(t *testing.T) {
+	params := SendEmailRequest{
+		From:    "no-reply@example.com",
+		To:      []string{"customer@example.com"},
+		Subject: "Your ticket",
+		Text:    "We received your ticket.",
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte(`"reply_to"`)) {
+		t.Errorf("expected reply_to to be omitted, got %s", b)
+	}
+
+	params.ReplyTo = "support@example.com"
+	b, err = json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`"reply_to":"support@example.com"`)) {
+		t.Errorf("expected reply_to to be sent, got %s", b)
+	}
+}
+
+func TestSendEmailSendAt(t *testing.T) {
+	var bodies []map[string]interface{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{Data: SendEmailData{RequestID: "req-1", Accepted: 1}})
+	})
+	defer server.Close()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	client.clock = &fakeClock{now: now}
+	params := &SendEmailRequest{
+		From:    "digest@example.com",
+		To:      []string{"user@example.com"},
+		Subject: "Your daily digest",
+		Text:    "Here is what you missed.",
+	}
+
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sendAt := now.Add(90*time.Minute + 500*time.Millisecond).In(time.FixedZone("CEST", 2*60*60))
+	params.SendAt = &sendAt
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	past := now.Add(-time.Minute)
+	params.SendAt = &past
+	if _, err := client.Emails.Send(context.Background(), params); err == nil || !strings.Contains(err.Error(), "not in the future") {
+		t.Errorf("expected past send time to be rejected, got %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if _, ok := bodies[0]["send_at"]; ok {
+		t.Errorf("expected send_at to be omitted when nil, got %v", bodies[0]["send_at"])
+	}
+	if got := bodies[1]["send_at"]; got != "2024-06-01T13:30:00Z" {
+		t.Errorf("expected send_at 2024-06-01T13:30:00Z, got %v", got)
+	}
+	if params.SendAt != &past {
+		t.Error("expected params to be left unchanged")
+	}
+
+	params.SendAt = &sendAt
+	_, err := client.Emails.Schedule(context.Background(), &ScheduleEmailRequest{
+		SendEmailRequest: *params,
+		ScheduledAt:      "2024-06-02T08:00:00Z",
+	})
+	if err == nil || !strings.Contains(err.Error(), "ScheduledAt") {
+		t.Errorf("expected Schedule to reject SendAt, got %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Errorf("expected no request for the rejected schedule, got %d requests", len(bodies))
+	}
+}
+
+func TestListEmailsSendingIP(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "from=2024-01-01&sending_ip=198.51.100.23" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListEmailsResponse{})
+	})
+	defer server.Close()
+
+	_, err := client.Emails.List(context.Background(), &ListEmailsParams{
+		SendingIP: "198.51.100.23",
+		From:      "2024-01-01",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSendEmailCustomHeaders(t *testing.T) {
+	var requests int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var raw struct {
+			Headers map[string]string `json:"headers"`
+		}
+		json.NewDecoder(r.Body).Decode(&raw)
+		want := map[string]string{
+			"X-Campaign-ID":    "spring-2024",
+			"List-Unsubscribe": "<https://example.com/unsub>",
+			"x-lowercase":      "kept",
+		}
+		if !reflect.DeepEqual(raw.Headers, want) {
+			t.Errorf("expected headers %v passed through verbatim, got %v", want, raw.Headers)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server.Close()
+
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+		Headers: map[string]string{
+			"X-Campaign-ID":    "spring-2024",
+			"List-Unsubscribe": "<https://example.com/unsub>",
+			"x-lowercase":      "kept",
+		},
+	}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"Subject", "to", "FROM", "Message-Id"} {
+		params.Headers = map[string]string{name: "x"}
+		_, err := client.Emails.Send(context.Background(), params)
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: expected collision error naming the header, got %v", name, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected colliding headers to be rejected before sending, got %d requests", requests)
+	}
+}
+
+func TestEmailProviderBreakdown(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/emails/stats/providers" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("from"); got != "2024-05-01T00:00:00Z" {
+			t.Errorf("unexpected from: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"providers":{"Gmail":{"sent":100,"delivered":98,"bounced":2},"Outlook":{"sent":50,"delivered":45,"bounced":5}}}}`))
+	})
+	defer server.Close()
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	providers, err := client.Emails.ProviderBreakdown(context.Background(), from, from.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(providers) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(providers))
+	}
+	if got := providers["Gmail"].DeliveryRate(); got != 0.98 {
+		t.Errorf("expected Gmail delivery rate 0.98, got %v", got)
+	}
+	if got := providers["Outlook"]; got.Bounced != 5 || got.DeliveryRate() != 0.9 {
+		t.Errorf("unexpected Outlook stats: %+v", got)
+	}
+}
+
+func TestResponseRequestIDFromHeader(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", "req_01HXYZ")
+		json.NewEncoder(w).Encode(SendEmailResponse{
+			Message: "Email queued for delivery.",
+			Data:    SendEmailData{RequestID: "tx-123", Accepted: 1},
+		})
+	})
+	defer server.Close()
+
+	resp, err := client.Emails.Send(context.Background(), &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.RequestID != "req_01HXYZ" {
+		t.Errorf("expected request ID from header, got %q", resp.RequestID)
+	}
+	if resp.Data.RequestID != "tx-123" {
+		t.Errorf("expected transmission ID to be unaffected, got %q", resp.Data.RequestID)
+	}
+
+	domains, err := client.Domains.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if domains.RequestID != "req_01HXYZ" {
+		t.Errorf("expected request ID on every response type, got %q", domains.RequestID)
+	}
+}
+
+func TestResponseRateLimit(t *testing.T) {
+	headers := http.Header{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range headers {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"domains":[]}}`))
+	})
+	defer server.Close()
+
+	headers.Set("X-RateLimit-Limit", "600")
+	headers.Set("X-RateLimit-Remaining", "598")
+	headers.Set("X-RateLimit-Reset", "1717243260")
+	resp, err := client.Domains.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	limit, remaining := 600, 598
+	want := &RateLimit{Limit: &limit, Remaining: &remaining, Reset: time.Unix(1717243260, 0)}
+	if !reflect.DeepEqual(resp.RateLimit, want) {
+		t.Errorf("expected %+v, got %+v", want, resp.RateLimit)
+	}
+
+	headers.Del("X-RateLimit-Reset")
+	headers.Set("X-RateLimit-Remaining", "0")
+	resp, err = client.Domains.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rl := resp.RateLimit; rl == nil || rl.Remaining == nil || *rl.Remaining != 0 || !rl.Reset.IsZero() {
+		t.Errorf("expected exhausted limit without reset, got %+v", resp.RateLimit)
+	}
+
+	headers.Del("X-RateLimit-Remaining")
+	resp, err = client.Domains.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rl := resp.RateLimit; rl == nil || rl.Limit == nil || *rl.Limit != 600 || rl.Remaining != nil {
+		t.Errorf("expected limit without remaining count, got %+v", resp.RateLimit)
+	}
+
+	headers = http.Header{}
+	resp, err = client.Domains.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.RateLimit != nil {
+		t.Errorf("expected nil rate limit without headers, got %+v", resp.RateLimit)
+	}
+}
+
+func TestUpdateWebhookOnlyActive(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		if got := strings.TrimSpace(string(raw)); got != `{"active":false}` {
+			t.Errorf("expected body with only active, got %s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UpdateWebhookResponse{
+			Data: Webhook{ID: "wh-123", Name: "Unchanged", Enabled: false},
+		})
+	})
+	defer server.Close()
+
+	active := false
+	resp, err := client.Webhooks.Update(context.Background(), "wh-123", &UpdateWebhookRequest{Active: &active})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.Name != "Unchanged" || resp.Data.Enabled {
+		t.Errorf("unexpected webhook: %+v", resp.Data)
+	}
+}
+
+func TestDeleteWebhookNoContentAndNotFound(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		if r.URL.RawPath == "/webhooks/wh%2Fstale" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Webhook not found.","error_code":"not_found"}`))
+	})
+	defer server.Close()
+
+	if _, err := client.Webhooks.Delete(context.Background(), "wh/stale"); err != nil {
+		t.Fatalf("expected 204 to be treated as success, got %v", err)
+	}
+	if _, err := client.Webhooks.Delete(context.Background(), "wh-missing"); !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestWebhookBuilder(t *testing.T) {
+	params, err := NewWebhookBuilder().
+		Name("Deliveries").
+		URL("https://example.com/webhook").
+		On(EventMessageDelivery).
+		On(EventMessageBounce).
+		BasicAuth("user", "pass").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &CreateWebhookRequest{
+		Name:         "Deliveries",
+		URL:          "https://example.com/webhook",
+		AuthType:     "basic",
+		AuthUsername: "user",
+		AuthPassword: "pass",
+		EventsMode:   "selected",
+		Events:       []string{EventMessageDelivery, EventMessageBounce},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("expected %+v, got %+v", want, params)
+	}
+
+	if params, err := NewWebhookBuilder().Name("All").URL("https://example.com/all").OnAll().Build(); err != nil || params.AuthType != "none" {
+		t.Errorf("expected valid unauthenticated webhook, got %+v, %v", params, err)
+	}
+
+	tests := map[string]*WebhookBuilder{
+		"missing URL":  NewWebhookBuilder().Name("x").On(EventMessageDelivery),
+		"http URL":     NewWebhookBuilder().Name("x").URL("http://example.com/hook").On(EventMessageDelivery),
+		"missing name": NewWebhookBuilder().URL("https://example.com/hook").On(EventMessageDelivery),
+		"no events":    NewWebhookBuilder().Name("x").URL("https://example.com/hook"),
+		"empty On":     NewWebhookBuilder().Name("x").URL("https://example.com/hook").On(),
+	}
+	for name, b := range tests {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := tests["missing URL"].Build(); err == nil || !strings.Contains(err.Error(), "URL is required") {
+		t.Errorf("expected missing URL error, got %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/status" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Status retrieved.","data":{"status":"maintenance","message":"Scheduled database upgrade until 02:00 UTC.","updated_at":"2024-06-01T00:00:00Z"}}`))
+	})
+	defer server.Close()
+
+	status, err := client.Status(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Data.Status != StatusMaintenance {
+		t.Errorf("expected status %q, got %q", StatusMaintenance, status.Data.Status)
+	}
+	if status.Data.Message != "Scheduled database upgrade until 02:00 UTC." {
+		t.Errorf("unexpected message: %q", status.Data.Message)
+	}
+}
+
+func TestErrorBodySnippet(t *testing.T) {
+	body := "<html>\n  <head><title>502 Bad Gateway</title></head>\n  <body><center><h1>502 Bad Gateway</h1></center><hr><center>nginx</center></body>\n</html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/empty" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...Option) *Client {
+		client, err := NewClientWithOptions("test-api-key", opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.SetBaseURL(server.URL)
+		return client
+	}
+
+	_, err := newClient().HealthCheck(context.Background())
+	if msg := err.(*Error).Message; msg != "Bad Gateway" {
+		t.Errorf("expected status text without the option, got %q", msg)
+	}
+
+	_, err = newClient(WithErrorBodySnippet(40)).HealthCheck(context.Background())
+	want := "Bad Gateway: <html> <head><title>502 Bad Gateway</tit…"
+	if msg := err.(*Error).Message; msg != want {
+		t.Errorf("expected %q, got %q", want, msg)
+	}
+
+	client := newClient(WithErrorBodySnippet(40))
+	req, _ := client.newRequest(context.Background(), "test.empty", http.MethodGet, "empty", nil)
+	_, err = client.do(req, nil)
+	if msg := err.(*Error).Message; msg != "Bad Gateway" {
+		t.Errorf("expected status text for empty body, got %q", msg)
+	}
+
+	if _, err := NewClientWithOptions("test-api-key", WithErrorBodySnippet(0)); err == nil {
+		t.Error("expected error for non-positive snippet length")
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	delivery := `{"id":"evt_1","type":"message.delivery","timestamp":"2024-06-01T12:00:00Z","webhook_id":"wh-123","data":{"event_id":"e1","type":"delivery","rcpt_to":"user@example.com","message_id":"msg-1"}}`
+	event, err := ParseWebhookEvent(strings.NewReader(delivery))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Type != EventMessageDelivery || event.WebhookID != "wh-123" || event.ID != "evt_1" {
+		t.Errorf("unexpected envelope: %+v", event)
+	}
+	if event.Data.RcptTo == nil || *event.Data.RcptTo != "user@example.com" {
+		t.Errorf("unexpected recipient: %v", event.Data.RcptTo)
+	}
+
+	bounce := `{"id":"evt_2","type":"message.bounce","timestamp":"2024-06-01T12:05:00Z","webhook_id":"wh-123","data":{"event_id":"e2","rcpt_to":"gone@example.com","reason":"550 5.1.1 user unknown","bounce_class":10}}`
+	event, err = ParseWebhookEvent(strings.NewReader(bounce))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Type != EventMessageBounce {
+		t.Errorf("expected bounce, got %q", event.Type)
+	}
+	if event.Data.Bounce == nil || event.Data.Bounce.BounceClass != 10 || event.Data.Bounce.Reason != "550 5.1.1 user unknown" {
+		t.Errorf("expected bounce details from the envelope type, got %+v", event.Data.Bounce)
+	}
+
+	if _, err := ParseWebhookEvent(strings.NewReader(`{"id":"evt_3"}`)); err == nil {
+		t.Error("expected error for event without type")
+	}
+	if _, err := ParseWebhookEvent(strings.NewReader(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseWebhookEventPayloadVersion(t *testing.T) {
+	unversioned := `{"id":"evt_1","type":"message.delivery","data":{"event_id":"e1","type":"delivery","rcpt_to":"user@example.com"}}`
+	v1 := `{"id":"evt_1","version":"1","type":"message.delivery","data":{"event_id":"e1","type":"delivery","rcpt_to":"user@example.com"}}`
+
+	a, err := ParseWebhookEvent(strings.NewReader(unversioned))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := ParseWebhookEvent(strings.NewReader(v1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.PayloadVersion != LatestWebhookPayloadVersion || b.PayloadVersion != WebhookPayloadV1 {
+		t.Errorf("unexpected versions %q and %q", a.PayloadVersion, b.PayloadVersion)
+	}
+	if b.Data.RcptTo == nil || *b.Data.RcptTo != "user@example.com" {
+		t.Errorf("unexpected recipient: %v", b.Data.RcptTo)
+	}
+
+	_, err = ParseWebhookEvent(strings.NewReader(`{"id":"evt_1","version":"99","type":"message.delivery"}`))
+	if err == nil || !strings.Contains(err.Error(), `unsupported webhook payload version "99"`) {
+		t.Errorf("expected unsupported version error, got %v", err)
+	}
+}
+
+func TestParseWebhookEvents(t *testing.T) {
+	single := `{"id":"evt_1","type":"message.delivery","data":{"event_id":"e1","type":"delivery"}}`
+	events, err := ParseWebhookEvents([]byte(single))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "evt_1" {
+		t.Errorf("expected one event, got %+v", events)
+	}
+
+	batch := ` [
+		{"id":"evt_1","type":"message.delivery","data":{"event_id":"e1","type":"delivery"}},
+		{"id":"evt_2","type":"message.bounce","data":{"event_id":"e2","reason":"mailbox full","bounce_class":20}}
+	]`
+	events, err = ParseWebhookEvents([]byte(batch))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "evt_1" || events[1].ID != "evt_2" {
+		t.Fatalf("expected two events in order, got %+v", events)
+	}
+	if events[1].Data.Bounce == nil || events[1].Data.Bounce.BounceClass != 20 {
+		t.Errorf("expected bounce details, got %+v", events[1].Data.Bounce)
+	}
+
+	_, err = ParseWebhookEvents([]byte(`[{"id":"evt_1","type":"message.delivery"},{"id":"evt_2"}]`))
+	if err == nil || !strings.Contains(err.Error(), "webhook event 1") {
+		t.Errorf("expected error naming the bad event, got %v", err)
+	}
+	if _, err := ParseWebhookEvents(nil); err == nil {
+		t.Error("expected error for empty body")
+	}
+}
+
+func TestWebhookMetadata(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body CreateWebhookRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CreateWebhookResponse{
+			Data: Webhook{ID: "wh-123", Name: body.Name, URL: body.URL, Metadata: body.Metadata},
+		})
+	})
+	defer server.Close()
+
+	metadata := map[string]string{"consumer": "billing"}
+	resp, err := client.Webhooks.Create(context.Background(), &CreateWebhookRequest{
+		Name:       "Billing",
+		URL:        "https://example.com/hooks",
+		AuthType:   "none",
+		EventsMode: "all",
+		Metadata:   metadata,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Data.Metadata, metadata) {
+		t.Errorf("expected metadata %v, got %v", metadata, resp.Data.Metadata)
+	}
+
+	delivery := `{"id":"evt_1","type":"message.delivery","webhook_id":"wh-123","metadata":{"consumer":"billing"},"data":{"event_id":"e1","type":"delivery"}}`
+	event, err := ParseWebhookEvent(strings.NewReader(delivery))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(event.Metadata, metadata) {
+		t.Errorf("expected delivered metadata %v, got %v", metadata, event.Metadata)
+	}
+}
+
+func TestListTemplatesIncludeMergeTags(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("include_merge_tags"); got != "true" {
+			t.Errorf("expected include_merge_tags=true, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"templates":[
+			{"id":1,"name":"Welcome","slug":"welcome","merge_tags":[{"key":"FIRST_NAME","required":true},{"key":"ITEMS","required":false,"type":"loop","children":[{"key":"NAME"}]}]},
+			{"id":2,"name":"Plain","slug":"plain","merge_tags":[]}
+		],"pagination":{"total":2,"per_page":25,"current_page":1,"last_page":1}}}`))
+	})
+	defer server.Close()
+
+	resp, err := client.Templates.List(context.Background(), &ListTemplatesParams{IncludeMergeTags: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tags := resp.Data.Templates[0].MergeTags
+	if len(tags) != 2 || tags[0].Key != "FIRST_NAME" || !tags[0].Required {
+		t.Fatalf("unexpected merge tags: %+v", tags)
+	}
+	if len(tags[1].Children) != 1 || tags[1].Children[0].Key != "NAME" {
+		t.Errorf("expected loop children, got %+v", tags[1].Children)
+	}
+	if len(resp.Data.Templates[1].MergeTags) != 0 {
+		t.Errorf("expected no merge tags, got %+v", resp.Data.Templates[1].MergeTags)
+	}
+}
+
+// fakeClock records requested sleeps instead of waiting.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sleeps = append(f.sleeps, d)
+	f.now = f.now.Add(d)
+	return ctx.Err()
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	var attempts int
+	var bodies []string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"Service unavailable."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(UpdateWebhookResponse{Data: Webhook{ID: "wh-1"}})
+	})
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client.clock = clock
+	if err := WithRetryConfig(RetryConfig{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})(client); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Webhooks.Update(context.Background(), "wh-1", &UpdateWebhookRequest{Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.ID != "wh-1" {
+		t.Errorf("unexpected response: %+v", resp.Data)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}; !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("expected sleeps %v, got %v", want, clock.sleeps)
+	}
+	for i, b := range bodies {
+		if b != `{"name":"Renamed"}` {
+			t.Errorf("attempt %d: expected the body to be replayed, got %q", i+1, b)
+		}
+	}
+
+	attempts = 0
+	_, err = client.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected retries to stop at MaxAttempts, got %d attempts", attempts)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var attempts int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"Too many requests."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server.Close()
+
+	clock := &fakeClock{}
+	client.clock = clock
+	client.retry = RetryConfig{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond}
+
+	_, err := client.Emails.Send(context.Background(), &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if want := []time.Duration{2 * time.Second}; !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("expected to wait %v, got %v", want, clock.sleeps)
+	}
+}
+
+func TestRetryAfterCappedAtMaxDelay(t *testing.T) {
+	var attempts int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"Too many requests."}`))
+			return
+		}
+		w.Write([]byte(`{"message":"ok","data":{"domains":[]}}`))
+	})
+	defer server.Close()
+
+	clock := &fakeClock{}
+	client.clock = clock
+	client.retry = RetryConfig{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 30 * time.Second}
+
+	if _, err := client.Domains.List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []time.Duration{30 * time.Second}; !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("expected Retry-After to be capped at %v, got %v", want, clock.sleeps)
+	}
+}
+
+func TestRetrySkipsUnsafeFailures(t *testing.T) {
+	var attempts int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"message":"Bad gateway."}`))
+	})
+	defer server.Close()
+
+	client.clock = &fakeClock{}
+	client.retry = RetryConfig{MaxAttempts: 3}
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		Text:    "Hello",
+	}
+
+	if _, err := client.Emails.Send(context.Background(), params); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected a send without idempotency key not to be retried on 5xx, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	client.Emails.Send(context.Background(), params, WithHeader("Idempotency-Key", "abc"))
+	if attempts != 3 {
+		t.Errorf("expected an idempotent send to be retried, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	client.Domains.Create(context.Background(), &CreateDomainRequest{Domain: "example.com"})
+	if attempts != 1 {
+		t.Errorf("expected a POST without idempotency key not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryRespectsContext(t *testing.T) {
+	var attempts int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	client.retry = RetryConfig{MaxAttempts: 5, BaseDelay: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.HealthCheck(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected cancellation before the second attempt, got %d attempts", attempts)
+	}
+}
+
+func TestWithRetryConfigValidation(t *testing.T) {
+	if _, err := NewClientWithOptions("key", WithRetryConfig(RetryConfig{MaxAttempts: -1})); err == nil {
+		t.Error("expected error for negative MaxAttempts")
+	}
+	client, err := NewClientWithOptions("key", WithBackoff(ConstantBackoff{Delay: time.Second}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg := client.Config().Retry; cfg.MaxAttempts != 3 || cfg.Backoff == nil {
+		t.Errorf("expected WithBackoff to enable retries, got %+v", cfg)
+	}
+	if got := client.retry.delay(2); got != time.Second {
+		t.Errorf("expected the backoff strategy to be used, got %v", got)
+	}
+
+	jittered := RetryConfig{BaseDelay: time.Second, Jitter: true}
+	for i := 0; i < 20; i++ {
+		if got := jittered.delay(1); got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("expected jittered delay in [500ms, 1s], got %v", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"2", 2 * time.Second, true},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+		{"-5", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSendEmailCharset(t *testing.T) {
+	var bodies []map[string]interface{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendEmailResponse{})
+	})
+	defer server.Close()
+
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Grüße",
+		Text:    "Grüße aus Köln",
+		Charset: "ISO-8859-1",
+	}
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	params.Charset = ""
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if bodies[0]["charset"] != "ISO-8859-1" {
+		t.Errorf("expected charset to be sent, got %v", bodies[0]["charset"])
+	}
+	if _, ok := bodies[1]["charset"]; ok {
+		t.Error("expected charset to be omitted by default")
+	}
+
+	params.Charset = "utf 8"
+	if _, err := client.Emails.Send(context.Background(), params); err == nil {
+		t.Error("expected error for invalid charset")
+	}
+}
+
+func TestRateLimitedRetryAfter(t *testing.T) {
+	retryAfter := ""
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"Too many requests.","error_code":"rate_limited"}`))
+	})
+	defer server.Close()
+	client.clock = &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"30", 30 * time.Second},
+		{"Mon, 01 Jan 2024 12:02:00 GMT", 2 * time.Minute},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		retryAfter = tt.header
+		_, err := client.ValidateAPIKey(context.Background())
+		if !IsRateLimited(err) {
+			t.Fatalf("%q: expected rate limited error, got %v", tt.header, err)
+		}
+		if got := err.(*Error).RetryAfter; got != tt.want {
+			t.Errorf("%q: expected RetryAfter %v, got %v", tt.header, tt.want, got)
+		}
+	}
+
+	if IsRateLimited(&Error{StatusCode: http.StatusNotFound}) {
+		t.Error("expected 404 not to be rate limited")
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	hc := &http.Client{Timeout: time.Minute}
+	client, err := NewClientWithOptions("key",
+		WithBaseURL("https://staging.example.com/api"),
+		WithHTTPClient(hc),
+		WithTimeout(5*time.Second),
+		WithUserAgent("my-app/2.0"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := client.Config()
+	if cfg.BaseURL != "https://staging.example.com/api/" {
+		t.Errorf("unexpected base URL: %q", cfg.BaseURL)
+	}
+	if cfg.Timeout != 5*time.Second {
+		t.Errorf("expected 5s timeout, got %v", cfg.Timeout)
+	}
+	if cfg.UserAgent != "my-app/2.0" {
+		t.Errorf("unexpected user agent: %q", cfg.UserAgent)
+	}
+	if hc.Timeout != time.Minute {
+		t.Errorf("expected the caller's HTTP client to be left unchanged, got timeout %v", hc.Timeout)
+	}
+
+	client, err = NewClientWithOptions("key", WithHTTPClient(hc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.httpClient != hc {
+		t.Error("expected the given HTTP client to be used")
+	}
+
+	var ua string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"status":"ok"}}`))
+	}))
+	defer server.Close()
+	client, err = NewClientWithOptions("key", WithBaseURL(server.URL), WithUserAgent("my-app/2.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ua != "my-app/2.0" {
+		t.Errorf("expected custom user agent to be sent, got %q", ua)
+	}
+
+	invalid := map[string]Option{
+		"unparseable URL":  WithBaseURL("://bad"),
+		"relative URL":     WithBaseURL("/api"),
+		"nil HTTP client":  WithHTTPClient(nil),
+		"empty user agent": WithUserAgent(" "),
+		"negative timeout": WithTimeout(-time.Second),
+	}
+	for name, opt := range invalid {
+		if _, err := NewClientWithOptions("key", opt); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSendRejectsOversizedSubstitutionData(t *testing.T) {
+	requests := 0
+	client, err := NewClientWithOptions("key", WithMaxSubstitutionBytes(1024))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"request_id":"r1","accepted":1,"rejected":0}}`))
+	}))
+	defer server.Close()
+	client.SetBaseURL(server.URL)
+
+	params := &SendEmailRequest{
+		From:             "sender@example.com",
+		To:               []string{"recipient@example.com"},
+		Subject:          "Hi",
+		Html:             "<p>Hi</p>",
+		SubstitutionData: map[string]string{"body": strings.Repeat("x", 600)},
+		Metadata:         map[string]string{"note": strings.Repeat("y", 600)},
+	}
+	_, err = client.Emails.Send(context.Background(), params)
+	if err == nil || !strings.Contains(err.Error(), "over the 1024 byte limit") {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected oversized send not to reach the server, got %d requests", requests)
+	}
+
+	params.Metadata = nil
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error under the cap: %v", err)
+	}
+
+	if got := NewClient("key").Config().MaxSubstitutionBytes; got != DefaultMaxSubstitutionBytes {
+		t.Errorf("expected default cap %d, got %d", DefaultMaxSubstitutionBytes, got)
+	}
+	if _, err := NewClientWithOptions("key", WithMaxSubstitutionBytes(0)); err == nil {
+		t.Error("expected error for non-positive cap")
+	}
+}
+
+func TestSendWithIdempotencyKey(t *testing.T) {
+	var keys [][]string
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Values("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"request_id":"r1","accepted":1,"rejected":0}}`))
+	})
+	defer server.Close()
+
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Receipt",
+		Html:    "<p>Thanks</p>",
+	}
+	ctx := context.Background()
+	if _, err := client.Emails.SendWithIdempotencyKey(ctx, params, "order-1234 receipt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Emails.Send(ctx, params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client.autoIdempotency = true
+	if _, err := client.Emails.SendWithIdempotencyKey(ctx, params, "explicit"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]string{{"order-1234 receipt"}, nil, {"explicit"}}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected Idempotency-Key headers %v, got %v", want, keys)
+	}
+
+	if _, err := client.Emails.SendWithIdempotencyKey(ctx, params, ""); err == nil {
+		t.Error("expected error for empty key")
+	}
+}
+
+func TestWithAcceptLanguage(t *testing.T) {
+	var langs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		langs = append(langs, r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Domaine introuvable."}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions("key", WithBaseURL(server.URL), WithAcceptLanguage("fr"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.Domains.Get(context.Background(), "example.com")
+	if e, ok := err.(*Error); !ok || e.Message != "Domaine introuvable." {
+		t.Errorf("expected localized error, got %v", err)
+	}
+	client.Domains.Get(context.Background(), "example.com", WithHeader("Accept-Language", "de"))
+
+	if want := []string{"fr", "de"}; !reflect.DeepEqual(langs, want) {
+		t.Errorf("expected Accept-Language %v, got %v", want, langs)
+	}
+	if client.Config().AcceptLanguage != "fr" {
+		t.Errorf("expected AcceptLanguage in config, got %q", client.Config().AcceptLanguage)
+	}
+	if _, err := NewClientWithOptions("key", WithAcceptLanguage("")); err == nil {
+		t.Error("expected error for empty language")
+	}
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	var versions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := "<none>"
+		if v := r.Header.Values("X-API-Version"); len(v) > 0 {
+			version = v[0]
+		}
+		versions = append(versions, version)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"status":"ok"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions("key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.HealthCheck(context.Background())
+
+	pinned, err := NewClientWithOptions("key", WithBaseURL(server.URL), WithAPIVersion("2024-06-01"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pinned.HealthCheck(context.Background())
+	pinned.HealthCheck(context.Background(), WithHeader("X-API-Version", "2025-06-01"))
+
+	if want := []string{"<none>", "2024-06-01", "2025-06-01"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("expected X-API-Version %v, got %v", want, versions)
+	}
+	if pinned.Config().APIVersion != "2024-06-01" {
+		t.Errorf("expected APIVersion in config, got %q", pinned.Config().APIVersion)
+	}
+	if _, err := NewClientWithOptions("key", WithAPIVersion(" ")); err == nil {
+		t.Error("expected error for empty version")
+	}
+}
+
+func TestRequestResponseHooks(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"message":"Domain created.","data":{"domain":"example.com"}}`))
+	}))
+	defer server.Close()
+
+	var requests, responses []string
+	client, err := NewClientWithOptions("sk_test123456",
+		WithBaseURL(server.URL),
+		WithOnRequest(func(r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if auth := r.Header.Get("Authorization"); auth != "Bearer *********3456" {
+				t.Errorf("expected redacted Authorization in hook, got %q", auth)
+			}
+			r.Header.Del("Authorization")
+		}),
+		WithOnResponse(func(r *http.Response) {
+			responses = append(responses, strconv.Itoa(r.StatusCode))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Domains.Create(context.Background(), &CreateDomainRequest{Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.Domain != "example.com" {
+		t.Errorf("expected response body to be decoded, got %+v", resp.Data)
+	}
+	if want := []string{"POST /domains"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+	if want := []string{"201"}; !reflect.DeepEqual(responses, want) {
+		t.Errorf("expected responses %v, got %v", want, responses)
+	}
+	if gotAuth != "Bearer sk_test123456" {
+		t.Errorf("expected hook not to affect the sent request, got Authorization %q", gotAuth)
+	}
+	if _, err := NewClientWithOptions("key", WithOnRequest(nil)); err == nil {
+		t.Error("expected error for nil hook")
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithTransportCarriesContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Email sent.","data":{"request_id":"req_1","accepted":1,"rejected":0}}`))
+	}))
+	defer server.Close()
+
+	type traceKey struct{}
+	var gotTrace interface{}
+	var gotOp string
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		gotTrace = r.Context().Value(traceKey{})
+		gotOp = OperationName(r.Context())
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	hc := &http.Client{Timeout: 5 * time.Second}
+	client, err := NewClientWithOptions("key", WithHTTPClient(hc), WithBaseURL(server.URL), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "span-1")
+	if _, err := client.Emails.Send(ctx, &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hi",
+		Text:    "Hello",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotTrace != "span-1" {
+		t.Errorf("expected caller's context in transport, got %v", gotTrace)
+	}
+	if gotOp != "emails.send" {
+		t.Errorf("expected operation emails.send, got %q", gotOp)
+	}
+	if hc.Transport != nil {
+		t.Error("expected the caller's HTTP client to be left unchanged")
+	}
+	if OperationName(context.Background()) != "" {
+		t.Error("expected no operation outside SDK requests")
+	}
+	if _, err := NewClientWithOptions("key", WithTransport(nil)); err == nil {
+		t.Error("expected error for nil transport")
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	attempts := 0
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message":"Service unavailable."}`))
+	})
+	defer server.Close()
+
+	clock := &fakeClock{}
+	client.clock = clock
+	for _, opt := range []Option{
+		WithRetryConfig(RetryConfig{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Minute}),
+		WithRetryBudget(2500 * time.Millisecond),
+	} {
+		if err := opt(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first call waits 1s, then stops because a 2s wait exceeds the
+	// remaining 1.5s; the second waits 1s from what is left; the third
+	// cannot retry at all.
+	for i, want := range []int{2, 2, 1} {
+		attempts = 0
+		_, err := client.Domains.List(context.Background())
+		if e, ok := err.(*Error); !ok || e.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("call %d: expected the last 503 error, got %v", i+1, err)
+		}
+		if attempts != want {
+			t.Errorf("call %d: expected %d attempts, got %d", i+1, want, attempts)
+		}
+	}
+	if want := []time.Duration{time.Second, time.Second}; !reflect.DeepEqual(clock.sleeps, want) {
+		t.Errorf("expected sleeps %v, got %v", want, clock.sleeps)
+	}
+	if got := client.Config().RetryBudget; got != 2500*time.Millisecond {
+		t.Errorf("expected configured budget in config, got %v", got)
+	}
+	if _, err := NewClientWithOptions("key", WithRetryBudget(0)); err == nil {
+		t.Error("expected error for non-positive budget")
+	}
+}
+
+func TestRetryObserver(t *testing.T) {
+	attempts := 0
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"Too many requests."}`))
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message":"Bad gateway."}`))
+		default:
+			w.Write([]byte(`{"message":"ok","data":{"domains":[]}}`))
+		}
+	})
+	defer server.Close()
+
+	type retry struct {
+		attempt int
+		status  int
+		delay   time.Duration
+	}
+	var retries []retry
+	client.clock = &fakeClock{}
+	for _, opt := range []Option{
+		WithRetryConfig(RetryConfig{MaxAttempts: 3, BaseDelay: time.Second}),
+		WithRetryObserver(func(attempt int, err error, nextDelay time.Duration) {
+			e, _ := err.(*Error)
+			if e == nil {
+				t.Fatalf("expected *Error cause, got %v", err)
+			}
+			retries = append(retries, retry{attempt, e.StatusCode, nextDelay})
+		}),
+	} {
+		if err := opt(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := client.Domains.List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []retry{
+		{1, http.StatusTooManyRequests, 3 * time.Second},
+		{2, http.StatusBadGateway, 2 * time.Second},
+	}
+	if !reflect.DeepEqual(retries, want) {
+		t.Errorf("expected retries %+v, got %+v", want, retries)
+	}
+	if _, err := NewClientWithOptions("key", WithRetryObserver(nil)); err == nil {
+		t.Error("expected error for nil observer")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	var limitsRequests, sends int
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/account/limits":
+			limitsRequests++
+			w.Write([]byte(`{"message":"ok","data":{"max_recipients_per_message":2,"daily_limit":10000}}`))
+		case "/emails":
+			sends++
+			w.Write([]byte(`{"message":"ok","data":{"request_id":"r1","accepted":3,"rejected":0}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		Cc:      []string{"c@example.com"},
+		Subject: "Hi",
+		Html:    "<p>Hi</p>",
+	}
+	if _, err := client.Emails.Send(ctx, params); err != nil {
+		t.Fatalf("expected no recipient limit before Limits is called, got %v", err)
+	}
+
+	limits, err := client.Limits(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limits.MaxRecipientsPerMessage != 2 || limits.DailyLimit != 10000 {
+		t.Errorf("unexpected limits: %+v", limits)
+	}
+	if _, err := client.Limits(ctx); err != nil || limitsRequests != 1 {
+		t.Errorf("expected cached limits, got %d requests and error %v", limitsRequests, err)
+	}
+
+	_, err = client.Emails.Send(ctx, params)
+	if err == nil || !strings.Contains(err.Error(), "3 recipients exceeds the account limit of 2") {
+		t.Errorf("expected recipient limit error, got %v", err)
+	}
+	if sends != 1 {
+		t.Errorf("expected the over-limit send not to reach the server, got %d sends", sends)
+	}
+}
+
+func TestSigningKeyFetchDoesNotBlockOtherCallers(t *testing.T) {
+	var calls int32
+	fetching, release := make(chan struct{}), make(chan struct{})
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(fetching)
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"signing_key":"key"}}`))
+	})
+	defer server.Close()
+	defer close(release)
+
+	go client.Webhooks.SigningKey(context.Background())
+	<-fetching
+
+	got := make(chan error, 1)
+	go func() {
+		_, err := client.Webhooks.SigningKey(context.Background())
+		got <- err
+	}()
+	select {
+	case err := <-got:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("expected SigningKey not to wait for another caller's in-flight fetch")
+	}
+}
+
+func TestLimitsFetchDoesNotBlockSends(t *testing.T) {
+	fetching, release := make(chan struct{}), make(chan struct{})
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account/limits" {
+			close(fetching)
+			<-release
+			w.Write([]byte(`{"message":"ok","data":{"max_recipients_per_message":50}}`))
+			return
+		}
+		w.Write([]byte(`{"message":"ok","data":{"request_id":"r1","accepted":1,"rejected":0}}`))
+	})
+	defer server.Close()
+
+	limitsDone := make(chan error, 1)
+	go func() {
+		_, err := client.Limits(context.Background())
+		limitsDone <- err
+	}()
+	<-fetching
+
+	sent := make(chan error, 1)
+	go func() {
+		_, err := client.Emails.Send(context.Background(), &SendEmailRequest{
+			From:    "sender@example.com",
+			To:      []string{"a@example.com"},
+			Subject: "Hi",
+			Text:    "Hi",
+		})
+		sent <- err
+	}()
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("expected Send not to wait for the in-flight Limits fetch")
+	}
+
+	close(release)
+	if err := <-limitsDone; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limits, _ := client.Limits(context.Background()); limits.MaxRecipientsPerMessage != 50 {
+		t.Errorf("expected fetched limits to be cached, got %+v", limits)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates/render" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body RenderTemplateRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		name, ok := body.SubstitutionData["FIRST_NAME"].(string)
+		if !ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Unresolved merge tags.","error_code":"validation_error","errors":{"FIRST_NAME":["The FIRST_NAME merge tag is required."]}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(RenderTemplateResponse{
+			Data: RenderTemplateData{Html: "<h1>Hi " + name + "</h1>", Subject: "Welcome, " + name},
+		})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := client.Templates.Render(ctx, &RenderTemplateRequest{
+		TemplateSlug:     "welcome",
+		SubstitutionData: map[string]interface{}{"FIRST_NAME": "Ada"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.Html != "<h1>Hi Ada</h1>" || resp.Data.Subject != "Welcome, Ada" {
+		t.Errorf("unexpected rendered template: %+v", resp.Data)
+	}
+
+	_, err = client.Templates.Render(ctx, &RenderTemplateRequest{TemplateSlug: "welcome"})
+	if !IsValidationError(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if fields := err.(*Error).ToFieldMap(); fields["FIRST_NAME"] == "" {
+		t.Errorf("expected unresolved merge tag in errors, got %v", fields)
+	}
+
+	if _, err := client.Templates.Render(ctx, &RenderTemplateRequest{}); err == nil {
+		t.Error("expected error without a template slug")
+	}
+}
+
+func TestNewAttachmentFromFile(t *testing.T) {
+	dir := t.TempDir()
+	pdf := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	files := map[string][]byte{
+		"invoice.pdf": pdf,
+		"notes.txt":   []byte("hello\n"),
+		"logo":        []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+		"empty.txt":   {},
+	}
+	for name, data := range files {
+		if err := os.WriteFile(dir+"/"+name, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := NewAttachmentFromFile(dir + "/invoice.pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Name != "invoice.pdf" || a.Type != "application/pdf" || a.Data != base64.StdEncoding.EncodeToString(pdf) {
+		t.Errorf("unexpected pdf attachment: %+v", a)
+	}
+
+	a, err = NewAttachmentFromFile(dir + "/notes.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(a.Type, "text/plain") || a.Data != "aGVsbG8K" {
+		t.Errorf("unexpected text attachment: %+v", a)
+	}
+
+	a, err = NewAttachmentFromFile(dir + "/logo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Type != "image/png" {
+		t.Errorf("expected sniffed type image/png, got %q", a.Type)
+	}
+
+	if _, err := NewAttachmentFromFile(dir + "/empty.txt"); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("expected empty attachment error, got %v", err)
+	}
+	if _, err := NewAttachmentFromFile(dir + "/missing.pdf"); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := NewAttachmentFromReader("broken.bin", iotest.ErrReader(errors.New("disk error"))); err == nil {
+		t.Error("expected error for unreadable reader")
+	}
+}
+
+func TestDryRenderTemplate(t *testing.T) {
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates/42/dry-render" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["substitution_data"]["FIRST_NAME"] != "Ada" {
+			t.Errorf("unexpected substitution data: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","data":{"html":"<h1>Hi Ada</h1><p>{{COUPON}}</p>","subject":"Welcome","unresolved_tags":["COUPON"]}}`))
+	})
+	defer server.Close()
+
+	resp, err := client.Templates.DryRender(context.Background(), 42, map[string]interface{}{"FIRST_NAME": "Ada"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Data.UnresolvedTags, []string{"COUPON"}) {
+		t.Errorf("expected unresolved COUPON tag, got %v", resp.Data.UnresolvedTags)
+	}
+	if !strings.HasPrefix(resp.Data.Html, "<h1>Hi Ada</h1>") {
+		t.Errorf("unexpected html: %q", resp.Data.Html)
+	}
+}
+
+func TestInlineAttachmentSerialization(t *testing.T) {
+	inline, _ := json.Marshal(Attachment{Name: "logo.png", Type: "image/png", Data: "iVBORw0KGgo=", Disposition: DispositionInline, ContentID: "logo"})
+	if want := `{"name":"logo.png","type":"image/png","data":"iVBORw0KGgo=","disposition":"inline","content_id":"logo"}`; string(inline) != want {
+		t.Errorf("expected %s, got %s", want, inline)
+	}
+	plain, _ := json.Marshal(Attachment{Name: "invoice.pdf", Type: "application/pdf", Data: "JVBERi0="})
+	if want := `{"name":"invoice.pdf","type":"application/pdf","data":"JVBERi0="}`; string(plain) != want {
+		t.Errorf("expected %s, got %s", want, plain)
+	}
+
+	params := &SendEmailRequest{
+		From:        "sender@example.com",
+		To:          []string{"recipient@example.com"},
+		Subject:     "Hi",
+		Html:        `<img src="cid:logo">`,
+		Attachments: []Attachment{{Name: "logo.png", Type: "image/png", Data: "iVBORw0KGgo=", Disposition: "embedded"}},
+	}
+	if err := params.validate(); err == nil {
+		t.Error("expected error for unknown disposition")
+	}
+}
+
+func TestSendRejectsOversizedAttachments(t *testing.T) {
+	client, err := NewClientWithOptions("key", WithMaxTotalAttachmentBytes(100))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attachment := func(name string, size int) Attachment {
+		return Attachment{Name: name, Type: "application/octet-stream", Data: base64.StdEncoding.EncodeToString(make([]byte, size))}
+	}
+	params := &SendEmailRequest{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Files",
+		Html:    "<p>Attached.</p>",
+	}
+
+	params.Attachments = []Attachment{attachment("huge.bin", 101)}
+	_, err = client.Emails.Send(context.Background(), params)
+	if err == nil || !strings.Contains(err.Error(), `"huge.bin" brings the total attachment size to 101 bytes`) {
+		t.Errorf("expected oversized attachment error, got %v", err)
+	}
+
+	params.Attachments = []Attachment{attachment("a.bin", 60), attachment("b.bin", 41)}
+	_, err = client.Emails.Send(context.Background(), params)
+	if err == nil || !strings.Contains(err.Error(), `"b.bin"`) || !strings.Contains(err.Error(), "over the 100 byte limit") {
+		t.Errorf("expected combined size error naming b.bin, got %v", err)
+	}
+
+	params.Attachments = []Attachment{attachment("a.bin", 60), attachment("b.bin", 40)}
+	if err := params.validateAttachmentSize(100, 100); err != nil {
+		t.Errorf("expected attachments at the limit to pass, got %v", err)
+	}
+
+	for size := 0; size < 6; size++ {
+		if got := decodedSize(base64.StdEncoding.EncodeToString(make([]byte, size))); got != int64(size) {
+			t.Errorf("decodedSize of %d bytes: got %d", size, got)
+		}
+	}
+	if got := NewClient("key").Config().MaxTotalAttachmentBytes; got != MaxTotalAttachmentBytes {
+		t.Errorf("expected default cap %d, got %d", MaxTotalAttachmentBytes, got)
+	}
+}
+
+func TestAttachmentSizeLimitsAtBoundary(t *testing.T) {
+	attachment := func(name string, size int) Attachment {
+		return Attachment{Name: name, Type: "application/octet-stream", Data: base64.StdEncoding.EncodeToString(make([]byte, size))}
+	}
+	params := &SendEmailRequest{Attachments: []Attachment{attachment("max.bin", MaxAttachmentBytes)}}
+	if err := params.validateAttachmentSize(MaxAttachmentBytes, MaxTotalAttachmentBytes); err != nil {
+		t.Errorf("expected attachment of exactly MaxAttachmentBytes to pass, got %v", err)
+	}
+	params.Attachments = []Attachment{attachment("over.bin", MaxAttachmentBytes+1)}
+	err := params.validateAttachmentSize(MaxAttachmentBytes, MaxTotalAttachmentBytes)
+	if err == nil || !strings.Contains(err.Error(), `"over.bin" is 10485761 bytes`) {
+		t.Errorf("expected per-attachment limit error, got %v", err)
+	}
+
+	client, err := NewClientWithOptions("key", WithMaxAttachmentBytes(MaxAttachmentBytes+1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := params.validateAttachmentSize(client.maxAttachmentBytes, client.maxTotalAttachmentBytes); err != nil {
+		t.Errorf("expected raised limit to allow the attachment, got %v", err)
+	}
+	if _, err := NewClientWithOptions("key", WithMaxAttachmentBytes(-1)); err == nil {
+		t.Error("expected error for non-positive limit")
+	}
+}
+
+func TestParseInboundEmail(t *testing.T) {
+	plain := `{"message_id":"<abc@mail.example.com>","from":"Jane Doe <jane@example.com>","to":["support@acme.test"],"subject":"Re: Order #1234","text":"Where is my order?","received_at":"2024-06-01T12:00:00Z"}`
+	email, err := ParseInboundEmail([]byte(plain))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if email.From != "Jane Doe <jane@example.com>" || email.Subject != "Re: Order #1234" || email.Text != "Where is my order?" {
+		t.Errorf("unexpected email: %+v", email)
+	}
+	if !reflect.DeepEqual(email.To, []string{"support@acme.test"}) || len(email.Attachments) != 0 {
+		t.Errorf("unexpected recipients or attachments: %+v", email)
+	}
+
+	withAttachment := `{"from":"jane@example.com","to":["support@acme.test"],"subject":"Screenshot","html":"<p>See attached</p>","attachments":[{"name":"error.txt","type":"text/plain","data":"aGVsbG8K"}]}`
+	email, err = ParseInboundEmail([]byte(withAttachment))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(email.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(email.Attachments))
+	}
+	if a := email.Attachments[0]; a.Name != "error.txt" || a.Type != "text/plain" || string(a.Content) != "hello\n" {
+		t.Errorf("unexpected attachment: %+v", a)
+	}
+
+	if _, err := ParseInboundEmail([]byte(`{"from":"jane@example.com","attachments":[{"name":"x","data":"not base64!"}]}`)); err == nil {
+		t.Error("expected error for invalid attachment data")
+	}
+	if _, err := ParseInboundEmail([]byte(`{"subject":"no sender"}`)); err == nil {
+		t.Error("expected error for email without sender")
+	}
+}
+
+func TestGetManyTemplates(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]bool{}
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.Method+" "+r.URL.RequestURI()] = true
+		mu.Unlock()
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/templates/"))
+		w.Header().Set("Content-Type", "application/json")
+		if id == 404 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Template not found."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(GetTemplateResponse{
+			Data: TemplateDetail{ID: id, Name: fmt.Sprintf("Template %d", id)},
+		})
+	})
+	defer server.Close()
+
+	templates, err := client.Templates.GetMany(context.Background(), []int{1, 404, 3})
+	errs, ok := err.(TemplateErrors)
+	if !ok {
+		t.Fatalf("expected TemplateErrors, got %v", err)
+	}
+	if len(errs) != 1 || !IsNotFound(errs[404]) {
+		t.Errorf("expected only template 404 to fail with not found, got %v", errs)
+	}
+	if len(templates) != 2 || templates[1].Data.Name != "Template 1" || templates[3].Data.ID != 3 {
+		t.Errorf("unexpected templates: %v", templates)
+	}
+	if !strings.Contains(err.Error(), "404 (lettr: 404 Template not found.)") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+	want := map[string]bool{"GET /templates/1": true, "GET /templates/404": true, "GET /templates/3": true}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected templates to be fetched by ID at %v, got %v", want, paths)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ids := make([]int, 2*getManyConcurrency)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	templates, err = client.Templates.GetMany(ctx, ids)
+	errs, _ = err.(TemplateErrors)
+	if len(templates) != 0 || len(errs) != len(ids) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected every fetch to fail with context.Canceled, got %d templates and %v", len(templates), err)
+	}
+}
@@ -0,0 +1,387 @@
+package lettr
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created with NewClientWithOptions.
+type Option func(*Client) error
+
+// NewClientWithOptions creates a new Lettr API client with the given API key
+// and applies opts in order. It returns an error if any option is invalid.
+//
+// Example:
+//
+//	client, err := lettr.NewClientWithOptions("your-api-key",
+//	    lettr.WithBaseURL("https://staging.lettr.com/api/"),
+//	    lettr.WithTimeout(10*time.Second),
+//	    lettr.WithAutoIdempotency(),
+//	)
+func NewClientWithOptions(apiKey string, opts ...Option) (*Client, error) {
+	c := NewClient(apiKey)
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvAPIKey  = "LETTR_API_KEY"
+	EnvBaseURL = "LETTR_BASE_URL"
+	EnvTimeout = "LETTR_TIMEOUT"
+)
+
+// NewClientFromEnv creates a client configured from the environment:
+// LETTR_API_KEY (required), LETTR_BASE_URL (optional) and LETTR_TIMEOUT
+// (optional, a Go duration such as "10s"). opts are applied afterwards, so
+// they take precedence over the environment.
+//
+// Example:
+//
+//	client, err := lettr.NewClientFromEnv()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	apiKey := os.Getenv(EnvAPIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("lettr: %s is not set", EnvAPIKey)
+	}
+
+	c := NewClient(apiKey)
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		if err := c.SetBaseURL(baseURL); err != nil {
+			return nil, fmt.Errorf("lettr: invalid %s %q: %w", EnvBaseURL, baseURL, err)
+		}
+	}
+	if raw := os.Getenv(EnvTimeout); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("lettr: invalid %s %q: want a non-negative duration such as \"30s\"", EnvTimeout, raw)
+		}
+		c.httpClient.Timeout = timeout
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// WithBaseURL sends requests to rawURL instead of the default API URL. The
+// URL must be absolute, e.g. "https://staging.lettr.com/api/".
+func WithBaseURL(rawURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("lettr: invalid base URL %q", rawURL)
+		}
+		return c.SetBaseURL(rawURL)
+	}
+}
+
+// WithHTTPClient makes the client send requests with hc. Options that
+// adjust the HTTP client, such as WithTimeout, should come after it.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("lettr: HTTP client must not be nil")
+		}
+		c.httpClient = hc
+		return nil
+	}
+}
+
+// WithTransport sets the RoundTripper used for API requests, e.g. one
+// instrumented for tracing. Requests carry the caller's context, and
+// OperationName reports which SDK call issued them. The HTTP client is
+// copied, so a client passed to WithHTTPClient is left unchanged.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		if rt == nil {
+			return fmt.Errorf("lettr: transport must not be nil")
+		}
+		hc := *c.httpClient
+		hc.Transport = rt
+		c.httpClient = &hc
+		return nil
+	}
+}
+
+// WithUserAgent replaces the User-Agent header sent with each request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) error {
+		if strings.TrimSpace(ua) == "" {
+			return fmt.Errorf("lettr: user agent must not be empty")
+		}
+		c.userAgent = ua
+		return nil
+	}
+}
+
+// WithAPIVersion pins the API version by sending it in the X-API-Version
+// header. Without it no header is sent and the API serves its default
+// version. Responses from any version are decoded into the same types, so
+// fields the version renamed or removed come back as zero values. Use
+// WithHeader to override the version for a single call.
+func WithAPIVersion(v string) Option {
+	return func(c *Client) error {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("lettr: API version must not be empty")
+		}
+		c.apiVersion = v
+		return nil
+	}
+}
+
+// WithAcceptLanguage sends lang (e.g. "fr" or "fr-CA, fr;q=0.9") as the
+// Accept-Language header so that API error messages come back localized.
+// Use WithHeader to override it for a single call.
+func WithAcceptLanguage(lang string) Option {
+	return func(c *Client) error {
+		if strings.TrimSpace(lang) == "" {
+			return fmt.Errorf("lettr: accept language must not be empty")
+		}
+		c.acceptLanguage = lang
+		return nil
+	}
+}
+
+// WithTimeout sets the overall timeout for each HTTP request (zero means no
+// timeout). The HTTP client is copied, so a client passed to WithHTTPClient
+// is left unchanged.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			return fmt.Errorf("lettr: timeout must not be negative, got %v", d)
+		}
+		hc := *c.httpClient
+		hc.Timeout = d
+		c.httpClient = &hc
+		return nil
+	}
+}
+
+// WithAutoIdempotency makes Emails.Send and Emails.Schedule attach an
+// Idempotency-Key header derived from the request content, so that retrying
+// an identical request is deduplicated by the API while distinct requests
+// never collide. See SendEmailRequest.IdempotencyKey.
+func WithAutoIdempotency() Option {
+	return func(c *Client) error {
+		c.autoIdempotency = true
+		return nil
+	}
+}
+
+// WithNormalizeRecipients makes Emails.Send and Emails.Schedule reduce
+// display-name recipients ("Jane Doe <jane@example.com>") in To, Cc and Bcc to
+// their bare addresses before sending. Display names are dropped.
+func WithNormalizeRecipients() Option {
+	return func(c *Client) error {
+		c.normalizeRecipients = true
+		return nil
+	}
+}
+
+// WithErrorBodySnippet includes up to n bytes of the response body in
+// Error.Message when an error response is not JSON, such as an HTML page from
+// a gateway returning 502. Without it, such errors only carry the status
+// text. Empty bodies always fall back to the status text.
+func WithErrorBodySnippet(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("lettr: error body snippet length must be positive, got %d", n)
+		}
+		c.errorBodySnippet = n
+		return nil
+	}
+}
+
+// WithMaxSubstitutionBytes changes the cap on the combined serialized size
+// of a send's SubstitutionData and Metadata from DefaultMaxSubstitutionBytes
+// to n. Sends over the cap fail before any request is made.
+func WithMaxSubstitutionBytes(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("lettr: max substitution bytes must be positive, got %d", n)
+		}
+		c.maxSubstitutionBytes = n
+		return nil
+	}
+}
+
+// WithMaxAttachmentBytes raises (or lowers) the cap on the decoded size of a
+// single inline attachment from MaxAttachmentBytes to n, for accounts on
+// plans with higher limits.
+func WithMaxAttachmentBytes(n int64) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("lettr: max attachment bytes must be positive, got %d", n)
+		}
+		c.maxAttachmentBytes = n
+		return nil
+	}
+}
+
+// WithMaxTotalAttachmentBytes raises (or lowers) the cap on the combined
+// decoded size of a send's inline attachments from MaxTotalAttachmentBytes
+// to n, for accounts on plans with higher limits.
+func WithMaxTotalAttachmentBytes(n int64) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("lettr: max total attachment bytes must be positive, got %d", n)
+		}
+		c.maxTotalAttachmentBytes = n
+		return nil
+	}
+}
+
+// WithOnRequest registers fn to be called before each HTTP attempt, including
+// retries, for logging or tracing. fn receives a copy of the request, so
+// changing it has no effect on what is sent. The body is not included, to
+// avoid leaking message content into logs, and the API key in the
+// Authorization header is redacted as in Client.Config.
+//
+// Example:
+//
+//	lettr.WithOnRequest(func(r *http.Request) {
+//	    log.Printf("lettr: %s %s", r.Method, r.URL)
+//	})
+func WithOnRequest(fn func(*http.Request)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("lettr: request hook must not be nil")
+		}
+		c.onRequest = fn
+		return nil
+	}
+}
+
+// WithOnResponse registers fn to be called after each HTTP attempt that
+// receives a response, including failed and retried ones. fn receives a copy
+// of the response without its body, which the client still decodes.
+//
+// Example:
+//
+//	lettr.WithOnResponse(func(r *http.Response) {
+//	    log.Printf("lettr: %s %s -> %d", r.Request.Method, r.Request.URL, r.StatusCode)
+//	})
+func WithOnResponse(fn func(*http.Response)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("lettr: response hook must not be nil")
+		}
+		c.onResponse = fn
+		return nil
+	}
+}
+
+// WithRetryConfig enables automatic retries of transient failures (429 and,
+// where safe, 5xx responses) as described by cfg. See RetryConfig.
+//
+// Example:
+//
+//	client, err := lettr.NewClientWithOptions("your-api-key",
+//	    lettr.WithRetryConfig(lettr.DefaultRetryConfig()),
+//	)
+func WithRetryConfig(cfg RetryConfig) Option {
+	return func(c *Client) error {
+		if err := cfg.validate(); err != nil {
+			return err
+		}
+		c.retry = cfg
+		return nil
+	}
+}
+
+// WithRetryBudget caps the total time the client spends waiting between
+// retries, shared across all calls, e.g. to bound the worst-case latency of
+// a large Campaign against a flaky API. Once a retry's wait would exceed
+// what is left, it is not attempted and the call returns the last error.
+// The budget does not refill. It only applies when retries are enabled.
+func WithRetryBudget(maxTotal time.Duration) Option {
+	return func(c *Client) error {
+		if maxTotal <= 0 {
+			return fmt.Errorf("lettr: retry budget must be positive, got %v", maxTotal)
+		}
+		c.retryBudget = &retryBudget{total: maxTotal, remaining: maxTotal}
+		return nil
+	}
+}
+
+// WithRetryObserver registers fn to be called before each retry wait, e.g.
+// to count retries in metrics. attempt is the 1-based attempt that failed,
+// err is its *Error (carrying the status code and any Retry-After), and
+// nextDelay is how long the client will wait before trying again. Only
+// retried HTTP responses are reported: network errors are never retried, so
+// they are returned from the call and do not reach fn. It has no effect
+// unless retries are enabled with WithRetryConfig.
+//
+// Example:
+//
+//	lettr.WithRetryObserver(func(attempt int, err error, nextDelay time.Duration) {
+//	    if apiErr, ok := err.(*lettr.Error); ok {
+//	        retries.WithLabelValues(strconv.Itoa(apiErr.StatusCode)).Inc()
+//	    }
+//	})
+func WithRetryObserver(fn func(attempt int, err error, nextDelay time.Duration)) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("lettr: retry observer must not be nil")
+		}
+		c.retryObserver = fn
+		return nil
+	}
+}
+
+// WithBackoff sets the strategy used to compute retry waits. If retries are
+// not otherwise configured, it enables them with DefaultRetryConfig's
+// attempt count.
+func WithBackoff(b BackoffStrategy) Option {
+	return func(c *Client) error {
+		if b == nil {
+			return fmt.Errorf("lettr: backoff strategy must not be nil")
+		}
+		if c.retry.MaxAttempts == 0 {
+			c.retry.MaxAttempts = DefaultRetryConfig().MaxAttempts
+		}
+		c.retry.Backoff = b
+		return nil
+	}
+}
+
+// RequestOption customizes a single API call. Every service method accepts
+// zero or more request options after its regular arguments.
+type RequestOption func(*http.Request) error
+
+// WithHeader sets a header on a single API call, replacing any value the
+// client would otherwise send. The Authorization header is managed by the
+// client and cannot be overridden.
+//
+// Example:
+//
+//	resp, err := client.Emails.Send(ctx, params,
+//	    lettr.WithHeader("X-Feature-Flag", "new-pipeline"),
+//	)
+func WithHeader(key, value string) RequestOption {
+	return func(req *http.Request) error {
+		if key == "" {
+			return fmt.Errorf("lettr: header name must not be empty")
+		}
+		if http.CanonicalHeaderKey(key) == "Authorization" {
+			return fmt.Errorf("lettr: header %q cannot be set per call", key)
+		}
+		req.Header.Set(key, value)
+		return nil
+	}
+}
@@ -21,11 +21,17 @@ type Project struct {
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 	Emoji     *string `json:"emoji"`
+
+	// TemplateCount is the number of templates in the project. It is zero
+	// when the API does not report it.
+	TemplateCount int `json:"template_count,omitempty"`
 }
 
 // ListProjectsParams contains the query parameters for listing projects.
 type ListProjectsParams struct {
-	// PerPage is the number of results per page (1-100, default 25).
+	// PerPage is the number of results per page (1-100, default 25). Zero
+	// uses the default; values outside the range are rejected before the
+	// request is sent.
 	PerPage int
 
 	// Page is the page number (default 1).
@@ -34,6 +40,7 @@ type ListProjectsParams struct {
 
 // ListProjectsResponse is the response from listing projects.
 type ListProjectsResponse struct {
+	ResponseMeta
 	Message string           `json:"message"`
 	Data    ListProjectsData `json:"data"`
 }
@@ -51,9 +58,12 @@ type ListProjectsData struct {
 // Example:
 //
 //	projects, err := client.Projects.List(ctx, nil)
-func (s *ProjectService) List(ctx context.Context, params *ListProjectsParams) (*ListProjectsResponse, error) {
+func (s *ProjectService) List(ctx context.Context, params *ListProjectsParams, opts ...RequestOption) (*ListProjectsResponse, error) {
 	path := "projects"
 	if params != nil {
+		if err := validatePerPage(params.PerPage); err != nil {
+			return nil, err
+		}
 		q := url.Values{}
 		if params.PerPage > 0 {
 			q.Set("per_page", strconv.Itoa(params.PerPage))
@@ -66,7 +76,7 @@ func (s *ProjectService) List(ctx context.Context, params *ListProjectsParams) (
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "projects.list", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -77,3 +87,29 @@ func (s *ProjectService) List(ctx context.Context, params *ListProjectsParams) (
 	}
 	return &resp, nil
 }
+
+// GetProjectResponse is the response from getting a single project.
+type GetProjectResponse struct {
+	ResponseMeta
+	Message string  `json:"message"`
+	Data    Project `json:"data"`
+}
+
+// Default retrieves the team's default project, which endpoints that accept
+// an optional project ID fall back to when none is given.
+//
+// Example:
+//
+//	project, err := client.Projects.Default(ctx)
+func (s *ProjectService) Default(ctx context.Context, opts ...RequestOption) (*Project, error) {
+	req, err := s.client.newRequest(ctx, "projects.default", http.MethodGet, "projects/default", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetProjectResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
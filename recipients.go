@@ -0,0 +1,71 @@
+package lettr
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/mail"
+	"sort"
+)
+
+// SampleRecipients deterministically selects round(fraction*len(recipients))
+// of the recipients, e.g. for canary sends. The same recipients, fraction and
+// seed always produce the same subset, returned in the original order.
+// It returns an error if fraction is outside [0, 1] or NaN.
+//
+// Example:
+//
+//	canary, err := lettr.SampleRecipients(subscribers, 0.05, 42)
+func SampleRecipients(recipients []string, fraction float64, seed int64) ([]string, error) {
+	if !(fraction >= 0 && fraction <= 1) {
+		return nil, fmt.Errorf("lettr: sample fraction must be in [0, 1], got %v", fraction)
+	}
+
+	n := int(math.Round(fraction * float64(len(recipients))))
+	if n == 0 {
+		return []string{}, nil
+	}
+
+	picked := rand.New(rand.NewSource(seed)).Perm(len(recipients))[:n]
+	sort.Ints(picked)
+
+	sample := make([]string, n)
+	for i, idx := range picked {
+		sample[i] = recipients[idx]
+	}
+	return sample, nil
+}
+
+// FormatFrom combines an address and a display name into a single header
+// value such as "Jane Doe <jane@example.com>", quoting or encoding the name
+// as needed. An empty name returns email unchanged. Sends take From and
+// FromName separately, so this is for places that need one string, e.g.
+// ReplyTo or recipient lists.
+//
+// Example:
+//
+//	lettr.FormatFrom("jane@example.com", "Doe, Jane") // `"Doe, Jane" <jane@example.com>`
+func FormatFrom(email, name string) string {
+	if name == "" {
+		return email
+	}
+	return (&mail.Address{Name: name, Address: email}).String()
+}
+
+// bareAddresses returns addrs with any display names stripped, so
+// "Jane Doe <jane@example.com>" becomes "jane@example.com". Addresses that
+// fail to parse are returned unchanged for the API to reject.
+func bareAddresses(addrs []string) []string {
+	if len(addrs) == 0 {
+		return addrs
+	}
+	out := make([]string, len(addrs))
+	for i, addr := range addrs {
+		if parsed, err := mail.ParseAddress(addr); err == nil {
+			out[i] = parsed.Address
+		} else {
+			out[i] = addr
+		}
+	}
+	return out
+}
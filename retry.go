@@ -0,0 +1,178 @@
+package lettr
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retryAfterHeader is the response header telling clients how long to wait.
+const retryAfterHeader = "Retry-After"
+
+// RetryConfig controls automatic retries of failed requests. Only transient
+// failures are retried: 429 Too Many Requests always, since the request was
+// not processed, and 5xx responses only for idempotent methods (GET, PUT,
+// DELETE, ...) or requests carrying an Idempotency-Key header, so that a
+// send is never duplicated. A Retry-After header on the response takes
+// precedence over the computed delay, but is still capped at MaxDelay.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values of 1 or less disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the wait before the first retry; it doubles with every
+	// further retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed wait and any Retry-After wait (zero means
+	// no cap).
+	MaxDelay time.Duration
+
+	// Jitter randomizes each computed wait between half and all of its
+	// value, so that many clients do not retry in lockstep.
+	Jitter bool
+
+	// Backoff, if set, computes the waits instead of BaseDelay, MaxDelay
+	// and Jitter.
+	Backoff BackoffStrategy
+}
+
+// DefaultRetryConfig returns a retry configuration suitable for most
+// callers: 3 attempts with jittered exponential backoff from 500ms to 10s.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      true,
+	}
+}
+
+// validate checks that the configuration is usable.
+func (r RetryConfig) validate() error {
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("lettr: retry MaxAttempts must not be negative, got %d", r.MaxAttempts)
+	}
+	if r.BaseDelay < 0 || r.MaxDelay < 0 {
+		return fmt.Errorf("lettr: retry delays must not be negative")
+	}
+	return nil
+}
+
+// delay returns the wait before the given 1-based retry.
+func (r RetryConfig) delay(retry int) time.Duration {
+	if r.Backoff != nil {
+		return r.Backoff.NextDelay(retry)
+	}
+	d := ExponentialBackoff{Base: r.BaseDelay, Max: r.MaxDelay}.NextDelay(retry)
+	if r.Jitter && d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	return d
+}
+
+// shouldRetry reports whether resp is a transient failure that is safe to
+// retry for req.
+func shouldRetry(req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if resp.StatusCode < 500 {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(idempotencyKeyHeader) != ""
+}
+
+// parseRetryAfter parses a Retry-After header value in either delta-seconds
+// ("120") or HTTP-date form. It reports false if the value is missing or
+// malformed. Dates in the past yield zero.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// retryBudget bounds the total time a client spends waiting between
+// retries, across all calls. It is safe for concurrent use.
+type retryBudget struct {
+	mu        sync.Mutex
+	total     time.Duration
+	remaining time.Duration
+}
+
+// take reserves d from the budget, reporting false (and reserving nothing)
+// if less than d remains.
+func (b *retryBudget) take(d time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if d > b.remaining {
+		return false
+	}
+	b.remaining -= d
+	return true
+}
+
+// send performs req, retrying transient failures according to c.retry and
+// c.retryBudget, and reporting each retry to c.retryObserver. The request
+// body is replayed via req.GetBody, which newRequest always sets.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		c.observeRequest(req)
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("lettr: request failed: %w", err)
+		}
+		c.observeResponse(resp)
+		if attempt >= c.retry.MaxAttempts || !shouldRetry(req, resp) {
+			return resp, nil
+		}
+
+		delay, ok := parseRetryAfter(resp.Header.Get(retryAfterHeader), c.clock.Now())
+		if !ok {
+			delay = c.retry.delay(attempt)
+		} else if c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay {
+			delay = c.retry.MaxDelay
+		}
+		if c.retryBudget != nil && !c.retryBudget.take(delay) {
+			return resp, nil
+		}
+		cause := c.parseError(resp) // also drains the body for connection reuse
+		resp.Body.Close()
+		if c.retryObserver != nil {
+			c.retryObserver(attempt, cause, delay)
+		}
+
+		if err := c.clock.Sleep(req.Context(), delay); err != nil {
+			return nil, fmt.Errorf("lettr: request failed: %w", err)
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("lettr: failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
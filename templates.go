@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // TemplateService handles communication with the template-related endpoints
@@ -23,6 +27,10 @@ type Template struct {
 	FolderID  int    `json:"folder_id"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+
+	// MergeTags lists the template's merge tags. It is only populated by
+	// List when ListTemplatesParams.IncludeMergeTags is set.
+	MergeTags []MergeTag `json:"merge_tags,omitempty"`
 }
 
 // MergeTag represents a merge tag extracted from template content.
@@ -45,15 +53,39 @@ type ListTemplatesParams struct {
 	// default project if not set.
 	ProjectID int
 
-	// PerPage is the number of results per page (1-100, default 25).
+	// FolderID restricts results to templates in this folder. Combine with
+	// ProjectID when the folder belongs to a non-default project.
+	FolderID int
+
+	// PerPage is the number of results per page (1-100, default 25). Zero
+	// uses the default; values outside the range are rejected before the
+	// request is sent.
 	PerPage int
 
 	// Page is the page number (default 1).
 	Page int
+
+	// CreatedAfter restricts results to templates created at or after this
+	// time. The zero value applies no lower bound.
+	CreatedAfter time.Time
+
+	// CreatedBefore restricts results to templates created at or before this
+	// time. The zero value applies no upper bound.
+	CreatedBefore time.Time
+
+	// IncludeMergeTags requests each template's merge tags in Template.MergeTags,
+	// saving a GetMergeTags call per template.
+	IncludeMergeTags bool
+
+	// Extra holds additional query parameters for filters the SDK does not
+	// model yet. Parameters set by the fields above take precedence over
+	// extras with the same name.
+	Extra url.Values
 }
 
 // ListTemplatesResponse is the response from listing templates.
 type ListTemplatesResponse struct {
+	ResponseMeta
 	Message string            `json:"message"`
 	Data    ListTemplatesData `json:"data"`
 }
@@ -64,6 +96,97 @@ type ListTemplatesData struct {
 	Pagination PagePagination `json:"pagination"`
 }
 
+// NextParams returns a copy of params positioned at the next page, or nil if
+// this was the last page. An empty page, or a LastPage of zero, is treated
+// as the last page.
+func (r *ListTemplatesResponse) NextParams(params *ListTemplatesParams) *ListTemplatesParams {
+	next := ListTemplatesParams{}
+	if params != nil {
+		next = *params
+	}
+	page := r.Data.Pagination.CurrentPage
+	if page == 0 {
+		page = next.Page
+	}
+	if page == 0 {
+		page = 1
+	}
+	if len(r.Data.Templates) == 0 || page >= r.Data.Pagination.LastPage {
+		return nil
+	}
+	next.Page = page + 1
+	return &next
+}
+
+// TemplateIterator walks every template matching a ListTemplatesParams,
+// fetching pages as needed. Create one with TemplateService.ListAll.
+type TemplateIterator struct {
+	ctx     context.Context
+	service *TemplateService
+	params  *ListTemplatesParams
+	opts    []RequestOption
+	page    []Template
+	current *Template
+	err     error
+}
+
+// ListAll returns an iterator over all templates matching params, walking
+// from params.Page (default 1) to the last page. ProjectID, PerPage and the
+// other filters apply to every page. Pass nil for params to use defaults.
+//
+// Example:
+//
+//	it := client.Templates.ListAll(ctx, &lettr.ListTemplatesParams{ProjectID: 7})
+//	for it.Next() {
+//	    fmt.Println(it.Template().Slug)
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+func (s *TemplateService) ListAll(ctx context.Context, params *ListTemplatesParams, opts ...RequestOption) *TemplateIterator {
+	first := ListTemplatesParams{}
+	if params != nil {
+		first = *params
+	}
+	return &TemplateIterator{ctx: ctx, service: s, params: &first, opts: opts}
+}
+
+// Next advances to the next template, fetching the next page when the
+// current one is exhausted. It returns false when there are no more
+// templates, the context is cancelled, or a request fails; check Err
+// afterwards.
+func (it *TemplateIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || it.params == nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		resp, err := it.service.List(it.ctx, it.params, it.opts...)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = resp.Data.Templates
+		it.params = resp.NextParams(it.params)
+	}
+	it.current = &it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Template returns the template at the iterator's current position.
+func (it *TemplateIterator) Template() *Template {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TemplateIterator) Err() error {
+	return it.err
+}
+
 // PagePagination holds page-based pagination info.
 type PagePagination struct {
 	Total       int `json:"total"`
@@ -92,6 +215,7 @@ type CreateTemplateRequest struct {
 
 // CreateTemplateResponse is the response from creating a template.
 type CreateTemplateResponse struct {
+	ResponseMeta
 	Message string             `json:"message"`
 	Data    CreateTemplateData `json:"data"`
 }
@@ -115,25 +239,45 @@ type CreateTemplateData struct {
 // Example:
 //
 //	templates, err := client.Templates.List(ctx, nil)
-func (s *TemplateService) List(ctx context.Context, params *ListTemplatesParams) (*ListTemplatesResponse, error) {
+func (s *TemplateService) List(ctx context.Context, params *ListTemplatesParams, opts ...RequestOption) (*ListTemplatesResponse, error) {
 	path := "templates"
 	if params != nil {
-		q := url.Values{}
+		if err := validatePerPage(params.PerPage); err != nil {
+			return nil, err
+		}
+		if !params.CreatedAfter.IsZero() && !params.CreatedBefore.IsZero() && params.CreatedAfter.After(params.CreatedBefore) {
+			return nil, fmt.Errorf("lettr: CreatedAfter (%s) is after CreatedBefore (%s)",
+				params.CreatedAfter.Format(time.RFC3339), params.CreatedBefore.Format(time.RFC3339))
+		}
+
+		q := cloneValues(params.Extra)
 		if params.ProjectID > 0 {
 			q.Set("project_id", strconv.Itoa(params.ProjectID))
 		}
+		if params.FolderID > 0 {
+			q.Set("folder_id", strconv.Itoa(params.FolderID))
+		}
 		if params.PerPage > 0 {
 			q.Set("per_page", strconv.Itoa(params.PerPage))
 		}
 		if params.Page > 0 {
 			q.Set("page", strconv.Itoa(params.Page))
 		}
+		if !params.CreatedAfter.IsZero() {
+			q.Set("created_after", params.CreatedAfter.UTC().Format(time.RFC3339))
+		}
+		if !params.CreatedBefore.IsZero() {
+			q.Set("created_before", params.CreatedBefore.UTC().Format(time.RFC3339))
+		}
+		if params.IncludeMergeTags {
+			q.Set("include_merge_tags", "true")
+		}
 		if encoded := q.Encode(); encoded != "" {
 			path += "?" + encoded
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "templates.list", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -153,8 +297,12 @@ func (s *TemplateService) List(ctx context.Context, params *ListTemplatesParams)
 //	    Name: "Welcome Email",
 //	    Html: "<h1>Hello {{FIRST_NAME}}!</h1>",
 //	})
-func (s *TemplateService) Create(ctx context.Context, params *CreateTemplateRequest) (*CreateTemplateResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodPost, "templates", params)
+func (s *TemplateService) Create(ctx context.Context, params *CreateTemplateRequest, opts ...RequestOption) (*CreateTemplateResponse, error) {
+	if params != nil && params.Html != "" && params.Json != "" {
+		return nil, fmt.Errorf("lettr: template Html and Json are mutually exclusive")
+	}
+
+	req, err := s.client.newRequest(ctx, "templates.create", http.MethodPost, "templates", params, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -169,17 +317,109 @@ func (s *TemplateService) Create(ctx context.Context, params *CreateTemplateRequ
 // TemplateDetail represents detailed information about a template,
 // including version info and content.
 type TemplateDetail struct {
-	ID            int    `json:"id"`
-	Name          string `json:"name"`
-	Slug          string `json:"slug"`
-	ProjectID     int    `json:"project_id"`
-	FolderID      int    `json:"folder_id"`
-	ActiveVersion *int   `json:"active_version"`
-	VersionsCount int    `json:"versions_count"`
-	Html          string `json:"html,omitempty"`
-	Json          string `json:"json,omitempty"`
-	CreatedAt     string `json:"created_at"`
-	UpdatedAt     string `json:"updated_at"`
+	ID            int        `json:"id"`
+	Name          string     `json:"name"`
+	Slug          string     `json:"slug"`
+	ProjectID     int        `json:"project_id"`
+	FolderID      int        `json:"folder_id"`
+	ActiveVersion *int       `json:"active_version"`
+	VersionsCount int        `json:"versions_count"`
+	MergeTags     []MergeTag `json:"merge_tags,omitempty"`
+	Html          string     `json:"html,omitempty"`
+	Json          string     `json:"json,omitempty"`
+	CreatedAt     string     `json:"created_at"`
+	UpdatedAt     string     `json:"updated_at"`
+}
+
+// getManyConcurrency is the number of requests GetMany runs at once.
+const getManyConcurrency = 4
+
+// TemplateErrors maps each template ID that could not be fetched to the
+// error returned for it.
+type TemplateErrors map[int]error
+
+// Error implements the error interface.
+func (e TemplateErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var sb strings.Builder
+	sb.WriteString("lettr: templates not fetched:")
+	for _, id := range ids {
+		sb.WriteString(fmt.Sprintf(" %d (%v);", id, e[id]))
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+// Unwrap returns the per-template errors so errors.Is and errors.As can
+// match any of them.
+func (e TemplateErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// GetMany fetches several templates by ID, a few at a time. It returns the
+// templates that were fetched, keyed by ID, and, if any failed, a
+// TemplateErrors holding the error for each of the others. Once ctx is done
+// no further fetches start, and the remaining IDs fail with ctx's error.
+//
+// Example:
+//
+//	templates, err := client.Templates.GetMany(ctx, []int{1, 2, 3})
+//	if errs, ok := err.(lettr.TemplateErrors); ok {
+//	    for id, err := range errs {
+//	        log.Printf("template %d: %v", id, err)
+//	    }
+//	}
+func (s *TemplateService) GetMany(ctx context.Context, ids []int, opts ...RequestOption) (map[int]*GetTemplateResponse, error) {
+	var (
+		mu      sync.Mutex
+		results = make(map[int]*GetTemplateResponse, len(ids))
+		errs    = TemplateErrors{}
+	)
+	forEachConcurrently(ctx, len(ids), getManyConcurrency, func(i int) {
+		resp, err := s.getByID(ctx, ids[i], opts...)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[ids[i]] = err
+			return
+		}
+		results[ids[i]] = resp
+	}, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[ids[i]] = err
+	})
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
+
+// getByID retrieves a single template by its numeric ID. Get looks
+// templates up by slug, so GetMany addresses them by ID here instead.
+func (s *TemplateService) getByID(ctx context.Context, id int, opts ...RequestOption) (*GetTemplateResponse, error) {
+	path := fmt.Sprintf("templates/%d", id)
+
+	req, err := s.client.newRequest(ctx, "templates.get", http.MethodGet, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetTemplateResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
 // GetTemplateParams contains optional query parameters for getting a template.
@@ -190,6 +430,7 @@ type GetTemplateParams struct {
 
 // GetTemplateResponse is the response from getting a single template.
 type GetTemplateResponse struct {
+	ResponseMeta
 	Message string         `json:"message"`
 	Data    TemplateDetail `json:"data"`
 }
@@ -201,7 +442,7 @@ type GetTemplateResponse struct {
 // Example:
 //
 //	template, err := client.Templates.Get(ctx, "welcome-email", nil)
-func (s *TemplateService) Get(ctx context.Context, slug string, params *GetTemplateParams) (*GetTemplateResponse, error) {
+func (s *TemplateService) Get(ctx context.Context, slug string, params *GetTemplateParams, opts ...RequestOption) (*GetTemplateResponse, error) {
 	path := fmt.Sprintf("templates/%s", url.PathEscape(slug))
 	if params != nil {
 		q := url.Values{}
@@ -213,7 +454,7 @@ func (s *TemplateService) Get(ctx context.Context, slug string, params *GetTempl
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "templates.get", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +496,7 @@ type UpdateTemplateData struct {
 
 // UpdateTemplateResponse is the response from updating a template.
 type UpdateTemplateResponse struct {
+	ResponseMeta
 	Message string             `json:"message"`
 	Data    UpdateTemplateData `json:"data"`
 }
@@ -266,10 +508,13 @@ type UpdateTemplateResponse struct {
 //	updated, err := client.Templates.Update(ctx, "welcome-email", &lettr.UpdateTemplateRequest{
 //	    Html: "<h1>Updated Hello {{FIRST_NAME}}!</h1>",
 //	})
-func (s *TemplateService) Update(ctx context.Context, slug string, params *UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+func (s *TemplateService) Update(ctx context.Context, slug string, params *UpdateTemplateRequest, opts ...RequestOption) (*UpdateTemplateResponse, error) {
+	if params != nil && params.Html != "" && params.Json != "" {
+		return nil, fmt.Errorf("lettr: template Html and Json are mutually exclusive")
+	}
 	path := fmt.Sprintf("templates/%s", url.PathEscape(slug))
 
-	req, err := s.client.newRequest(ctx, http.MethodPut, path, params)
+	req, err := s.client.newRequest(ctx, "templates.update", http.MethodPut, path, params, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -281,6 +526,248 @@ func (s *TemplateService) Update(ctx context.Context, slug string, params *Updat
 	return &resp, nil
 }
 
+// moveTemplateRequest is the request body for moving a template.
+type moveTemplateRequest struct {
+	FolderID int `json:"folder_id"`
+}
+
+// Move moves a template into another folder, leaving its name and content
+// unchanged. The API rejects folders that belong to a different project.
+//
+// Example:
+//
+//	moved, err := client.Templates.Move(ctx, 42, 7)
+func (s *TemplateService) Move(ctx context.Context, id, folderID int, opts ...RequestOption) (*GetTemplateResponse, error) {
+	if folderID <= 0 {
+		return nil, fmt.Errorf("lettr: invalid folder ID %d", folderID)
+	}
+
+	path := fmt.Sprintf("templates/%d", id)
+
+	req, err := s.client.newRequest(ctx, "templates.move", http.MethodPatch, path, &moveTemplateRequest{FolderID: folderID}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetTemplateResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// sendTestTemplateRequest is the request body for sending a template test.
+type sendTestTemplateRequest struct {
+	To               []string               `json:"to"`
+	SubstitutionData map[string]interface{} `json:"substitution_data,omitempty"`
+}
+
+// RenderTemplateRequest is the request body for rendering a template.
+type RenderTemplateRequest struct {
+	// TemplateSlug is the template to render (required).
+	TemplateSlug string `json:"template_slug"`
+
+	// TemplateVersion renders a specific version instead of the active one.
+	TemplateVersion *int `json:"template_version,omitempty"`
+
+	// ProjectID is the project containing the template. Uses the team's
+	// default project if not set.
+	ProjectID *int `json:"project_id,omitempty"`
+
+	// SubstitutionData contains sample values for the template's merge tags.
+	SubstitutionData map[string]interface{} `json:"substitution_data,omitempty"`
+}
+
+// RenderTemplateResponse is the response from rendering a template.
+type RenderTemplateResponse struct {
+	ResponseMeta
+	Message string             `json:"message"`
+	Data    RenderTemplateData `json:"data"`
+}
+
+// RenderTemplateData contains the compiled template content.
+type RenderTemplateData struct {
+	Html    string `json:"html"`
+	Text    string `json:"text,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// Render compiles a template with the given substitution data without
+// sending anything, e.g. to catch broken merge tags before a send. Required
+// merge tags missing from SubstitutionData are reported as a validation
+// error (see IsValidationError), with Error.Errors keyed by merge tag.
+//
+// Example:
+//
+//	rendered, err := client.Templates.Render(ctx, &lettr.RenderTemplateRequest{
+//	    TemplateSlug:     "welcome-email",
+//	    SubstitutionData: map[string]interface{}{"FIRST_NAME": "Ada"},
+//	})
+//	if lettr.IsValidationError(err) {
+//	    log.Printf("missing merge tags: %v", err.(*lettr.Error).ToFieldMap())
+//	}
+func (s *TemplateService) Render(ctx context.Context, params *RenderTemplateRequest, opts ...RequestOption) (*RenderTemplateResponse, error) {
+	if params == nil || params.TemplateSlug == "" {
+		return nil, fmt.Errorf("lettr: template slug is required")
+	}
+
+	req, err := s.client.newRequest(ctx, "templates.render", http.MethodPost, "templates/render", params, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RenderTemplateResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// dryRenderTemplateRequest is the request body for dry-rendering a template.
+type dryRenderTemplateRequest struct {
+	SubstitutionData map[string]interface{} `json:"substitution_data,omitempty"`
+}
+
+// DryRenderResponse is the response from dry-rendering a template.
+type DryRenderResponse struct {
+	ResponseMeta
+	Message string        `json:"message"`
+	Data    DryRenderData `json:"data"`
+}
+
+// DryRenderData contains the compiled template content and any merge tags
+// the substitution data did not resolve.
+type DryRenderData struct {
+	Html    string `json:"html"`
+	Text    string `json:"text,omitempty"`
+	Subject string `json:"subject,omitempty"`
+
+	// UnresolvedTags lists every merge tag, required or not, that data did
+	// not provide a value for. Empty when all tags resolved.
+	UnresolvedTags []string `json:"unresolved_tags"`
+}
+
+// DryRender compiles the active version of a template with data and reports
+// the placeholders left unresolved, without sending anything. Unlike Render,
+// unresolved tags are listed in the response, including optional ones,
+// rather than returned as an error.
+//
+// Example:
+//
+//	dry, err := client.Templates.DryRender(ctx, 42, map[string]interface{}{"FIRST_NAME": "Ada"})
+//	if err == nil && len(dry.Data.UnresolvedTags) > 0 {
+//	    log.Printf("unresolved merge tags: %v", dry.Data.UnresolvedTags)
+//	}
+func (s *TemplateService) DryRender(ctx context.Context, id int, data map[string]interface{}, opts ...RequestOption) (*DryRenderResponse, error) {
+	path := fmt.Sprintf("templates/%d/dry-render", id)
+
+	req, err := s.client.newRequest(ctx, "templates.dry_render", http.MethodPost, path, &dryRenderTemplateRequest{
+		SubstitutionData: data,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DryRenderResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SendTest sends a test email rendered from the template's active version to
+// the given addresses, using data as sample substitution data.
+//
+// Example:
+//
+//	resp, err := client.Templates.SendTest(ctx, 42, []string{"designer@example.com"},
+//	    map[string]interface{}{"FIRST_NAME": "Ada"},
+//	)
+func (s *TemplateService) SendTest(ctx context.Context, id int, to []string, data map[string]interface{}, opts ...RequestOption) (*SendEmailResponse, error) {
+	path := fmt.Sprintf("templates/%d/test", id)
+
+	req, err := s.client.newRequest(ctx, "templates.send_test", http.MethodPost, path, &sendTestTemplateRequest{
+		To:               to,
+		SubstitutionData: data,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SendEmailResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TemplateStatsResponse is the response from getting template stats.
+type TemplateStatsResponse struct {
+	ResponseMeta
+	Message string        `json:"message"`
+	Data    TemplateStats `json:"data"`
+}
+
+// TemplateStats contains aggregate engagement totals for a template over a
+// window.
+type TemplateStats struct {
+	TemplateID int    `json:"template_id"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Sent       int    `json:"sent"`
+	Opened     int    `json:"opened"`
+	Clicked    int    `json:"clicked"`
+}
+
+// OpenRate returns the fraction (0-1) of sent emails that were opened, or 0
+// if nothing was sent.
+func (t TemplateStats) OpenRate() float64 {
+	if t.Sent == 0 {
+		return 0
+	}
+	return float64(t.Opened) / float64(t.Sent)
+}
+
+// ClickRate returns the fraction (0-1) of sent emails that were clicked, or
+// 0 if nothing was sent.
+func (t TemplateStats) ClickRate() float64 {
+	if t.Sent == 0 {
+		return 0
+	}
+	return float64(t.Clicked) / float64(t.Sent)
+}
+
+// Stats retrieves aggregate send, open and click totals for a template
+// between from and to. Use TemplateStats.OpenRate and ClickRate to compare
+// templates.
+//
+// Example:
+//
+//	stats, err := client.Templates.Stats(ctx, 42, time.Now().AddDate(0, 0, -30), time.Now())
+//	fmt.Printf("open rate %.1f%%\n", stats.Data.OpenRate()*100)
+func (s *TemplateService) Stats(ctx context.Context, id int, from, to time.Time, opts ...RequestOption) (*TemplateStatsResponse, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("lettr: from (%s) is after to (%s)",
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	q := url.Values{}
+	q.Set("from", from.UTC().Format(time.RFC3339))
+	q.Set("to", to.UTC().Format(time.RFC3339))
+	path := fmt.Sprintf("templates/%d/stats?%s", id, q.Encode())
+
+	req, err := s.client.newRequest(ctx, "templates.stats", http.MethodGet, path, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TemplateStatsResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // DeleteTemplateParams contains optional query parameters for deleting a template.
 type DeleteTemplateParams struct {
 	// ProjectID is the project containing the template.
@@ -289,6 +776,7 @@ type DeleteTemplateParams struct {
 
 // DeleteTemplateResponse is the response from deleting a template.
 type DeleteTemplateResponse struct {
+	ResponseMeta
 	Message string `json:"message"`
 }
 
@@ -299,7 +787,7 @@ type DeleteTemplateResponse struct {
 // Example:
 //
 //	resp, err := client.Templates.Delete(ctx, "welcome-email", nil)
-func (s *TemplateService) Delete(ctx context.Context, slug string, params *DeleteTemplateParams) (*DeleteTemplateResponse, error) {
+func (s *TemplateService) Delete(ctx context.Context, slug string, params *DeleteTemplateParams, opts ...RequestOption) (*DeleteTemplateResponse, error) {
 	path := fmt.Sprintf("templates/%s", url.PathEscape(slug))
 	if params != nil {
 		q := url.Values{}
@@ -311,7 +799,7 @@ func (s *TemplateService) Delete(ctx context.Context, slug string, params *Delet
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodDelete, path, nil)
+	req, err := s.client.newRequest(ctx, "templates.delete", http.MethodDelete, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -334,6 +822,7 @@ type GetMergeTagsParams struct {
 
 // GetMergeTagsResponse is the response from getting merge tags.
 type GetMergeTagsResponse struct {
+	ResponseMeta
 	Message string           `json:"message"`
 	Data    GetMergeTagsData `json:"data"`
 }
@@ -353,7 +842,7 @@ type GetMergeTagsData struct {
 // Example:
 //
 //	tags, err := client.Templates.GetMergeTags(ctx, "welcome-email", nil)
-func (s *TemplateService) GetMergeTags(ctx context.Context, slug string, params *GetMergeTagsParams) (*GetMergeTagsResponse, error) {
+func (s *TemplateService) GetMergeTags(ctx context.Context, slug string, params *GetMergeTagsParams, opts ...RequestOption) (*GetMergeTagsResponse, error) {
 	path := fmt.Sprintf("templates/%s/merge-tags", url.PathEscape(slug))
 	if params != nil {
 		q := url.Values{}
@@ -368,7 +857,7 @@ func (s *TemplateService) GetMergeTags(ctx context.Context, slug string, params
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "templates.get_merge_tags", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -391,6 +880,7 @@ type GetTemplateHtmlParams struct {
 
 // GetTemplateHtmlResponse is the response from getting template HTML.
 type GetTemplateHtmlResponse struct {
+	ResponseMeta
 	Success bool                `json:"success"`
 	Data    GetTemplateHtmlData `json:"data"`
 }
@@ -418,7 +908,7 @@ type HtmlMergeTag struct {
 //	    ProjectID: 1,
 //	    Slug:      "welcome-email",
 //	})
-func (s *TemplateService) GetHtml(ctx context.Context, params *GetTemplateHtmlParams) (*GetTemplateHtmlResponse, error) {
+func (s *TemplateService) GetHtml(ctx context.Context, params *GetTemplateHtmlParams, opts ...RequestOption) (*GetTemplateHtmlResponse, error) {
 	path := "templates/html"
 	if params != nil {
 		q := url.Values{}
@@ -433,7 +923,7 @@ func (s *TemplateService) GetHtml(ctx context.Context, params *GetTemplateHtmlPa
 		}
 	}
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "templates.get_html", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,226 @@
+package lettr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ValidateEmailAddress reports whether addr is a valid RFC 5322 address.
+// Both bare addresses ("jane@example.com") and display-name forms
+// ("Jane Doe <jane@example.com>") are accepted.
+func ValidateEmailAddress(addr string) error {
+	if _, err := mail.ParseAddress(addr); err != nil {
+		return fmt.Errorf("lettr: invalid email address %q: %w", addr, err)
+	}
+	return nil
+}
+
+// validateAddresses checks every address in addrs, naming field in the error.
+func validateAddresses(field string, addrs ...string) error {
+	for _, addr := range addrs {
+		if err := ValidateEmailAddress(addr); err != nil {
+			return fmt.Errorf("lettr: invalid %s address: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// maxPerPage is the largest page size the list endpoints accept.
+const maxPerPage = 100
+
+// validatePerPage rejects page sizes outside 1-100. Zero is allowed and means
+// the server default. Out-of-range values are rejected rather than clamped so
+// a caller never silently gets a different page size than they asked for.
+func validatePerPage(perPage int) error {
+	if perPage < 0 || perPage > maxPerPage {
+		return fmt.Errorf("lettr: PerPage must be between 1 and %d (or 0 for the default), got %d", maxPerPage, perPage)
+	}
+	return nil
+}
+
+// validate performs client-side checks on the request before it is sent.
+// Required fields are left to the API so that its validation errors are
+// reported unchanged; only values that are present are checked here.
+func (r *SendEmailRequest) validate() error {
+	if r.From != "" {
+		if err := validateAddresses("from", r.From); err != nil {
+			return err
+		}
+	}
+	if err := validateAddresses("to", r.To...); err != nil {
+		return err
+	}
+	if err := validateAddresses("cc", r.Cc...); err != nil {
+		return err
+	}
+	if err := validateAddresses("bcc", r.Bcc...); err != nil {
+		return err
+	}
+	if r.ReplyTo != "" {
+		if err := validateAddresses("reply_to", r.ReplyTo); err != nil {
+			return err
+		}
+	}
+	if r.Charset != "" && !isCharsetName(r.Charset) {
+		return fmt.Errorf("lettr: invalid charset %q", r.Charset)
+	}
+	if err := validateHeaders(r.Headers); err != nil {
+		return err
+	}
+	for _, a := range r.Attachments {
+		if err := a.validate(); err != nil {
+			return err
+		}
+	}
+	if r.Options != nil && r.Options.TrackingDomain != "" {
+		if err := validateHostname(r.Options.TrackingDomain); err != nil {
+			return fmt.Errorf("lettr: invalid tracking domain: %w", err)
+		}
+	}
+	if r.Options != nil && r.Options.Priority != "" {
+		if _, ok := priorityHeaders[r.Options.Priority]; !ok {
+			return fmt.Errorf("lettr: invalid priority %q (want %q, %q or %q)",
+				r.Options.Priority, PriorityHigh, PriorityNormal, PriorityLow)
+		}
+	}
+	return nil
+}
+
+// reservedHeaders are set by the API from request fields and cannot be
+// supplied through SendEmailRequest.Headers.
+var reservedHeaders = []string{
+	"From", "To", "Cc", "Bcc", "Reply-To", "Subject",
+	"Date", "Message-ID", "Content-Type", "Content-Transfer-Encoding", "MIME-Version",
+}
+
+// validateHeaders rejects custom headers that collide with headers the API
+// controls. Names are compared case-insensitively but sent unchanged.
+func validateHeaders(headers map[string]string) error {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("lettr: header name must not be empty")
+		}
+		for _, reserved := range reservedHeaders {
+			if strings.EqualFold(name, reserved) {
+				return fmt.Errorf("lettr: header %q is set by the API; use the corresponding SendEmailRequest field instead", name)
+			}
+		}
+	}
+	return nil
+}
+
+// isCharsetName reports whether name is syntactically a MIME charset name
+// (RFC 2978), such as "UTF-8" or "ISO-8859-1".
+func isCharsetName(name string) bool {
+	if len(name) > 40 {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("!#$%&'+-^_`{}~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateHostname checks that host is a fully qualified domain name such as
+// "track.example.com": dot-separated labels of letters, digits and hyphens.
+func validateHostname(host string) error {
+	if len(host) > 253 || !strings.Contains(host, ".") {
+		return fmt.Errorf("%q is not a fully qualified domain name", host)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("%q is not a fully qualified domain name", host)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return fmt.Errorf("%q contains invalid character %q", host, c)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that the attachment has exactly one content source and a
+// known disposition.
+func (a *Attachment) validate() error {
+	if (a.Data == "") == (a.PresignedURL == "") {
+		return fmt.Errorf("lettr: attachment %q must set exactly one of Data or PresignedURL", a.Name)
+	}
+	if a.PresignedURL != "" {
+		u, err := url.Parse(a.PresignedURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("lettr: attachment %q has invalid presigned URL %q", a.Name, a.PresignedURL)
+		}
+	}
+	switch a.Disposition {
+	case "", DispositionAttachment, DispositionInline:
+	default:
+		return fmt.Errorf("lettr: attachment %q has invalid disposition %q (want %q or %q)",
+			a.Name, a.Disposition, DispositionAttachment, DispositionInline)
+	}
+	return nil
+}
+
+// validateSubstitutionSize checks that SubstitutionData and Metadata together
+// serialize to at most max bytes, so oversized sends fail with a clear error
+// instead of an opaque 413 from the API.
+func (r *SendEmailRequest) validateSubstitutionSize(max int) error {
+	size := 0
+	for _, m := range []map[string]string{r.SubstitutionData, r.Metadata} {
+		if len(m) == 0 {
+			continue
+		}
+		b, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("lettr: failed to marshal substitution data: %w", err)
+		}
+		size += len(b)
+	}
+	if size > max {
+		return fmt.Errorf("lettr: substitution data and metadata are %d bytes serialized, over the %d byte limit", size, max)
+	}
+	return nil
+}
+
+// validateAttachmentSize checks that each inline attachment decodes to at
+// most maxEach bytes and that together they add up to at most maxTotal,
+// naming the attachment that crosses a limit. Attachments fetched from a
+// PresignedURL are not counted.
+func (r *SendEmailRequest) validateAttachmentSize(maxEach, maxTotal int64) error {
+	var total int64
+	for _, a := range r.Attachments {
+		size := decodedSize(a.Data)
+		if size > maxEach {
+			return fmt.Errorf("lettr: attachment %q is %d bytes, over the %d byte limit", a.Name, size, maxEach)
+		}
+		total += size
+		if total > maxTotal {
+			return fmt.Errorf("lettr: attachment %q brings the total attachment size to %d bytes, over the %d byte limit", a.Name, total, maxTotal)
+		}
+	}
+	return nil
+}
+
+// decodedSize returns the number of bytes the base64 string data decodes to,
+// without decoding it.
+func decodedSize(data string) int64 {
+	n := int64(len(data)) * 3 / 4
+	if strings.HasSuffix(data, "==") {
+		n -= 2
+	} else if strings.HasSuffix(data, "=") {
+		n--
+	}
+	return n
+}
@@ -0,0 +1,166 @@
+package lettr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Webhook payload versions understood by ParseWebhookEvent.
+const (
+	// WebhookPayloadV1 is the original payload format, with the event
+	// details under "data".
+	WebhookPayloadV1 = "1"
+
+	// LatestWebhookPayloadVersion is assumed for payloads that do not carry
+	// a version.
+	LatestWebhookPayloadVersion = WebhookPayloadV1
+)
+
+// webhookEventDecoders decodes each supported payload version.
+var webhookEventDecoders = map[string]func(raw []byte) (*WebhookEvent, error){
+	WebhookPayloadV1: decodeWebhookEventV1,
+}
+
+// WebhookEvent is a single event delivered to a webhook endpoint. Type is
+// one of the Event* constants, e.g. EventMessageDelivery or
+// EventMessageBounce.
+type WebhookEvent struct {
+	// ID uniquely identifies the delivery, for deduplicating retries.
+	ID string `json:"id"`
+
+	// PayloadVersion is the payload format the event was delivered in. It is
+	// LatestWebhookPayloadVersion when the payload did not say.
+	PayloadVersion string `json:"version,omitempty"`
+
+	// Type is the namespaced event type (see the Event* constants).
+	Type string `json:"type"`
+
+	// Timestamp is when the event occurred (ISO 8601).
+	Timestamp string `json:"timestamp"`
+
+	// WebhookID is the webhook the event was delivered to.
+	WebhookID string `json:"webhook_id"`
+
+	// Metadata echoes the webhook's custom key-value pairs, for routing
+	// deliveries that share one URL.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Data holds the event details, with the same fields as the events
+	// returned by EmailService.ListEvents.
+	Data EmailEvent `json:"data"`
+}
+
+// ParseWebhookEvent decodes a webhook delivery from r, typically an incoming
+// request body. The payload is decoded according to its version, or as
+// LatestWebhookPayloadVersion if it has none; unknown versions are an
+// error. It does not verify the delivery's authenticity.
+//
+// Example:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    event, err := lettr.ParseWebhookEvent(r.Body)
+//	    if err != nil {
+//	        http.Error(w, err.Error(), http.StatusBadRequest)
+//	        return
+//	    }
+//	    switch event.Type {
+//	    case lettr.EventMessageBounce:
+//	        log.Printf("bounce for %s: %s", *event.Data.RcptTo, event.Data.Bounce.Reason)
+//	    }
+//	}
+func ParseWebhookEvent(r io.Reader) (*WebhookEvent, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("lettr: failed to decode webhook event: %w", err)
+	}
+	return parseWebhookEvent(raw)
+}
+
+// parseWebhookEvent decodes a single event with the decoder for its
+// payload version.
+func parseWebhookEvent(raw []byte) (*WebhookEvent, error) {
+	var envelope struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("lettr: failed to decode webhook event: %w", err)
+	}
+	version := envelope.Version
+	if version == "" {
+		version = LatestWebhookPayloadVersion
+	}
+	decode, ok := webhookEventDecoders[version]
+	if !ok {
+		return nil, fmt.Errorf("lettr: unsupported webhook payload version %q", version)
+	}
+
+	event, err := decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	event.PayloadVersion = version
+	return event, nil
+}
+
+// decodeWebhookEventV1 decodes a WebhookPayloadV1 event.
+func decodeWebhookEventV1(raw []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, fmt.Errorf("lettr: failed to decode webhook event: %w", err)
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("lettr: webhook event has no type")
+	}
+
+	// Event payloads may omit the type inside data; fall back to the
+	// envelope's so the typed details are still populated.
+	if event.Data.Type == "" && event.Data.Raw != nil {
+		event.Data.Type = event.Type
+		if err := event.Data.decodeDetails(event.Data.Raw); err != nil {
+			return nil, fmt.Errorf("lettr: failed to decode webhook event: %w", err)
+		}
+	}
+	return &event, nil
+}
+
+// ParseWebhookEvents decodes a webhook delivery body that holds either a
+// single event or a JSON array of batched events, so one handler can serve
+// both. Events are returned in payload order.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	events, err := lettr.ParseWebhookEvents(body)
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	for _, event := range events {
+//	    handleEvent(event)
+//	}
+func ParseWebhookEvents(body []byte) ([]WebhookEvent, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		event, err := parseWebhookEvent(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return []WebhookEvent{*event}, nil
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(trimmed, &raws); err != nil {
+		return nil, fmt.Errorf("lettr: failed to decode webhook events: %w", err)
+	}
+	events := make([]WebhookEvent, len(raws))
+	for i, raw := range raws {
+		event, err := parseWebhookEvent(raw)
+		if err != nil {
+			return nil, fmt.Errorf("lettr: webhook event %d: %w", i, err)
+		}
+		events[i] = *event
+	}
+	return events, nil
+}
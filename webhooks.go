@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 )
 
 // WebhookService handles communication with the webhook-related endpoints
 // of the Lettr API.
 type WebhookService struct {
 	client *Client
+
+	// signingKeyMu guards signingKey, which caches the result of SigningKey.
+	signingKeyMu sync.Mutex
+	signingKey   string
 }
 
 // Webhook represents a webhook configuration.
@@ -25,6 +30,10 @@ type Webhook struct {
 	LastSuccessfulAt   *string   `json:"last_successful_at"`
 	LastFailureAt      *string   `json:"last_failure_at"`
 	LastStatus         *string   `json:"last_status"`
+
+	// Metadata holds custom key-value pairs that Lettr echoes in every
+	// delivered payload, e.g. for routing between consumers.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // Webhook event-type constants. The Lettr API uses namespaced strings
@@ -60,6 +69,7 @@ const (
 
 // ListWebhooksResponse is the response from listing webhooks.
 type ListWebhooksResponse struct {
+	ResponseMeta
 	Message string           `json:"message"`
 	Data    ListWebhooksData `json:"data"`
 }
@@ -71,6 +81,7 @@ type ListWebhooksData struct {
 
 // GetWebhookResponse is the response from getting a single webhook.
 type GetWebhookResponse struct {
+	ResponseMeta
 	Message string  `json:"message"`
 	Data    Webhook `json:"data"`
 }
@@ -80,8 +91,8 @@ type GetWebhookResponse struct {
 // Example:
 //
 //	webhooks, err := client.Webhooks.List(ctx)
-func (s *WebhookService) List(ctx context.Context) (*ListWebhooksResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodGet, "webhooks", nil)
+func (s *WebhookService) List(ctx context.Context, opts ...RequestOption) (*ListWebhooksResponse, error) {
+	req, err := s.client.newRequest(ctx, "webhooks.list", http.MethodGet, "webhooks", nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,10 +109,10 @@ func (s *WebhookService) List(ctx context.Context) (*ListWebhooksResponse, error
 // Example:
 //
 //	webhook, err := client.Webhooks.Get(ctx, "webhook-abc123")
-func (s *WebhookService) Get(ctx context.Context, webhookID string) (*GetWebhookResponse, error) {
+func (s *WebhookService) Get(ctx context.Context, webhookID string, opts ...RequestOption) (*GetWebhookResponse, error) {
 	path := fmt.Sprintf("webhooks/%s", url.PathEscape(webhookID))
 
-	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	req, err := s.client.newRequest(ctx, "webhooks.get", http.MethodGet, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +136,10 @@ type CreateWebhookRequest struct {
 	OAuthTokenURL     string   `json:"oauth_token_url,omitempty"`
 	EventsMode        string   `json:"events_mode"`
 	Events            []string `json:"events,omitempty"`
+
+	// Metadata holds custom key-value pairs that Lettr echoes in every
+	// delivered payload (see WebhookEvent.Metadata).
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // UpdateWebhookRequest represents the request body for updating a webhook.
@@ -143,16 +158,21 @@ type UpdateWebhookRequest struct {
 	OAuthTokenURL     string   `json:"oauth_token_url,omitempty"`
 	Events            []string `json:"events,omitempty"`
 	Active            *bool    `json:"active,omitempty"`
+
+	// Metadata replaces the webhook's custom key-value pairs when set.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // CreateWebhookResponse is the response from creating a webhook.
 type CreateWebhookResponse struct {
+	ResponseMeta
 	Message string  `json:"message"`
 	Data    Webhook `json:"data"`
 }
 
 // UpdateWebhookResponse is the response from updating a webhook.
 type UpdateWebhookResponse struct {
+	ResponseMeta
 	Message string  `json:"message"`
 	Data    Webhook `json:"data"`
 }
@@ -174,8 +194,8 @@ type UpdateWebhookResponse struct {
 //	        lettr.EventMessageBounce,
 //	    },
 //	})
-func (s *WebhookService) Create(ctx context.Context, params *CreateWebhookRequest) (*CreateWebhookResponse, error) {
-	req, err := s.client.newRequest(ctx, http.MethodPost, "webhooks", params)
+func (s *WebhookService) Create(ctx context.Context, params *CreateWebhookRequest, opts ...RequestOption) (*CreateWebhookResponse, error) {
+	req, err := s.client.newRequest(ctx, "webhooks.create", http.MethodPost, "webhooks", params, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -199,10 +219,10 @@ func (s *WebhookService) Create(ctx context.Context, params *CreateWebhookReques
 //	    URL:    "https://example.com/new-webhook",
 //	    Active: &active,
 //	})
-func (s *WebhookService) Update(ctx context.Context, webhookID string, params *UpdateWebhookRequest) (*UpdateWebhookResponse, error) {
+func (s *WebhookService) Update(ctx context.Context, webhookID string, params *UpdateWebhookRequest, opts ...RequestOption) (*UpdateWebhookResponse, error) {
 	path := fmt.Sprintf("webhooks/%s", url.PathEscape(webhookID))
 
-	req, err := s.client.newRequest(ctx, http.MethodPut, path, params)
+	req, err := s.client.newRequest(ctx, "webhooks.update", http.MethodPut, path, params, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +236,7 @@ func (s *WebhookService) Update(ctx context.Context, webhookID string, params *U
 
 // DeleteWebhookResponse is the response from deleting a webhook.
 type DeleteWebhookResponse struct {
+	ResponseMeta
 	Message string `json:"message"`
 }
 
@@ -224,10 +245,10 @@ type DeleteWebhookResponse struct {
 // Example:
 //
 //	resp, err := client.Webhooks.Delete(ctx, "webhook-abc123")
-func (s *WebhookService) Delete(ctx context.Context, webhookID string) (*DeleteWebhookResponse, error) {
+func (s *WebhookService) Delete(ctx context.Context, webhookID string, opts ...RequestOption) (*DeleteWebhookResponse, error) {
 	path := fmt.Sprintf("webhooks/%s", url.PathEscape(webhookID))
 
-	req, err := s.client.newRequest(ctx, http.MethodDelete, path, nil)
+	req, err := s.client.newRequest(ctx, "webhooks.delete", http.MethodDelete, path, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -238,3 +259,139 @@ func (s *WebhookService) Delete(ctx context.Context, webhookID string) (*DeleteW
 	}
 	return &resp, nil
 }
+
+// SigningKeyResponse is the response from getting the webhook signing key.
+type SigningKeyResponse struct {
+	ResponseMeta
+	Message string         `json:"message"`
+	Data    SigningKeyData `json:"data"`
+}
+
+// SigningKeyData contains the webhook signing key.
+type SigningKeyData struct {
+	// SigningKey is the PEM-encoded public key webhook deliveries are signed
+	// with.
+	SigningKey string `json:"signing_key"`
+}
+
+// SigningKey retrieves the PEM-encoded public key used to sign webhook
+// deliveries. The key is fetched once and cached for the lifetime of the
+// client; failed fetches are not cached.
+//
+// Example:
+//
+//	pemKey, err := client.Webhooks.SigningKey(ctx)
+//	block, _ := pem.Decode([]byte(pemKey))
+//	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+func (s *WebhookService) SigningKey(ctx context.Context, opts ...RequestOption) (string, error) {
+	s.signingKeyMu.Lock()
+	cached := s.signingKey
+	s.signingKeyMu.Unlock()
+	if cached != "" {
+		return cached, nil
+	}
+
+	// Fetch without holding signingKeyMu, so one slow fetch doesn't block
+	// other callers past their own ctx deadlines. Concurrent first calls
+	// may each fetch; the last one cached wins.
+	req, err := s.client.newRequest(ctx, "webhooks.signing_key", http.MethodGet, "webhooks/signing-key", nil, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var resp SigningKeyResponse
+	if _, err := s.client.do(req, &resp); err != nil {
+		return "", err
+	}
+
+	s.signingKeyMu.Lock()
+	s.signingKey = resp.Data.SigningKey
+	s.signingKeyMu.Unlock()
+
+	return resp.Data.SigningKey, nil
+}
+
+// WebhookBuilder builds a validated CreateWebhookRequest. Create one with
+// NewWebhookBuilder, chain the setters, then call Build.
+type WebhookBuilder struct {
+	req CreateWebhookRequest
+}
+
+// NewWebhookBuilder returns a builder for a webhook without authentication.
+//
+// Example:
+//
+//	params, err := lettr.NewWebhookBuilder().
+//	    Name("Deliveries").
+//	    URL("https://example.com/webhook").
+//	    On(lettr.EventMessageDelivery, lettr.EventMessageBounce).
+//	    BasicAuth("user", "pass").
+//	    Build()
+//	if err != nil {
+//	    return err
+//	}
+//	webhook, err := client.Webhooks.Create(ctx, params)
+func NewWebhookBuilder() *WebhookBuilder {
+	return &WebhookBuilder{req: CreateWebhookRequest{AuthType: "none"}}
+}
+
+// Name sets the webhook name.
+func (b *WebhookBuilder) Name(name string) *WebhookBuilder {
+	b.req.Name = name
+	return b
+}
+
+// URL sets the destination endpoint, which must use https.
+func (b *WebhookBuilder) URL(rawURL string) *WebhookBuilder {
+	b.req.URL = rawURL
+	return b
+}
+
+// On subscribes the webhook to the given events (see the Event* constants).
+// Repeated calls add to the list.
+func (b *WebhookBuilder) On(events ...string) *WebhookBuilder {
+	b.req.EventsMode = "selected"
+	b.req.Events = append(b.req.Events, events...)
+	return b
+}
+
+// OnAll subscribes the webhook to every event, replacing any selected with On.
+func (b *WebhookBuilder) OnAll() *WebhookBuilder {
+	b.req.EventsMode = "all"
+	b.req.Events = nil
+	return b
+}
+
+// BasicAuth makes Lettr authenticate deliveries with HTTP basic auth.
+func (b *WebhookBuilder) BasicAuth(username, password string) *WebhookBuilder {
+	b.req.AuthType = "basic"
+	b.req.AuthUsername = username
+	b.req.AuthPassword = password
+	return b
+}
+
+// Build validates the configuration and returns the request to pass to
+// WebhookService.Create. It requires a name, an https URL and at least one
+// event (or OnAll).
+func (b *WebhookBuilder) Build() (*CreateWebhookRequest, error) {
+	if b.req.Name == "" {
+		return nil, fmt.Errorf("lettr: webhook name is required")
+	}
+	if b.req.URL == "" {
+		return nil, fmt.Errorf("lettr: webhook URL is required")
+	}
+	u, err := url.Parse(b.req.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("lettr: webhook URL %q must be an absolute https URL", b.req.URL)
+	}
+	if b.req.EventsMode == "" || (b.req.EventsMode == "selected" && len(b.req.Events) == 0) {
+		return nil, fmt.Errorf("lettr: webhook must subscribe to at least one event")
+	}
+	if b.req.AuthType == "basic" && (b.req.AuthUsername == "" || b.req.AuthPassword == "") {
+		return nil, fmt.Errorf("lettr: webhook basic auth requires a username and password")
+	}
+
+	req := b.req
+	req.Events = append([]string(nil), b.req.Events...)
+	return &req, nil
+}